@@ -43,16 +43,25 @@ func (p *Program) String() string {
 type LetStatement struct {
 	Token *token.Token
 	Name  *Identifier
-	Value Expression
+	// Pattern is set instead of Name when the value is destructured
+	Pattern Pattern
+	Value   Expression
+	// Doc holds the doc comments written before the statement
+	Doc string
 }
 
 func (ls *LetStatement) statementNode()       {}
+func (ls *LetStatement) IsConst() bool        { return ls.Token.IsType(token.Const) }
 func (ls *LetStatement) TokenLiteral() string { return ls.Token.Literal }
 func (ls *LetStatement) String() string {
 	out := new(bytes.Buffer)
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -132,6 +141,50 @@ func (pe *PrefixExpression) String() string {
 	return out.String()
 }
 
+type PostfixExpression struct {
+	Token    *token.Token
+	Operator string
+	Left     Expression
+}
+
+func (pe *PostfixExpression) expressionNode()      {}
+func (pe *PostfixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PostfixExpression) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteByte('(')
+	out.WriteString(pe.Left.String())
+	out.WriteString(pe.Operator)
+	out.WriteByte(')')
+
+	return out.String()
+}
+
+type RangeExpression struct {
+	Token     *token.Token
+	Start     Expression
+	End       Expression
+	Step      Expression
+	Inclusive bool
+}
+
+func (re *RangeExpression) expressionNode()      {}
+func (re *RangeExpression) TokenLiteral() string { return re.Token.Literal }
+func (re *RangeExpression) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString("(")
+	out.WriteString(re.Start.String())
+	out.WriteString(re.Token.Literal)
+	out.WriteString(re.End.String())
+	if re.Step != nil {
+		out.WriteString(" step ")
+		out.WriteString(re.Step.String())
+	}
+	out.WriteString(")")
+	return out.String()
+}
+
 type InfixExpression struct {
 	Token    *token.Token
 	Operator string
@@ -205,7 +258,16 @@ func (bs *BlockStatement) String() string {
 type FunctionLiteralExpression struct {
 	Token      *token.Token
 	Parameters []*Identifier
-	Body       *BlockStatement
+	// Patterns holds the destructuring pattern of each parameter, or nil
+	// for parameters that are plain identifiers
+	Patterns []Pattern
+	Body     *BlockStatement
+	// Generator is set when the body yields, so calling the function
+	// returns a generator rather than running the body
+	Generator bool
+	// Doc holds the doc comments written before the function, or before
+	// the let statement it is assigned by
+	Doc string
 }
 
 func (fle *FunctionLiteralExpression) expressionNode()      {}
@@ -232,6 +294,9 @@ type CallExpression struct {
 	Token     *token.Token
 	Function  Expression // Identifier || FunctionLiteralExpression
 	Arguments []Expression
+	// Keywords holds the arguments passed by name, which follow the
+	// positional arguments
+	Keywords []*KeywordArgument
 }
 
 func (ce *CallExpression) expressionNode()      {}
@@ -243,6 +308,9 @@ func (ce *CallExpression) String() string {
 	for _, a := range ce.Arguments {
 		args = append(args, a.String())
 	}
+	for _, kw := range ce.Keywords {
+		args = append(args, kw.String())
+	}
 
 	out.WriteString(ce.Function.String())
 	out.WriteString("(")
@@ -251,6 +319,17 @@ func (ce *CallExpression) String() string {
 	return out.String()
 }
 
+// KeywordArgument is an argument passed by name, as in f(x, size = 2)
+type KeywordArgument struct {
+	Name  *Identifier
+	Value Expression
+}
+
+func (ka *KeywordArgument) TokenLiteral() string { return ka.Name.TokenLiteral() }
+func (ka *KeywordArgument) String() string {
+	return ka.Name.String() + " = " + ka.Value.String()
+}
+
 type StringLiteral struct {
 	Token *token.Token
 	Value string
@@ -266,6 +345,32 @@ func (sl *StringLiteral) String() string {
 	return out.String()
 }
 
+// InterpolatedString is a string with embedded expressions. Parts holds
+// the text around the expressions as StringLiterals and the expressions
+// themselves, in order.
+type InterpolatedString struct {
+	Token *token.Token
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode()      {}
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+func (is *InterpolatedString) String() string {
+	out := new(bytes.Buffer)
+	out.WriteByte('"')
+	for _, part := range is.Parts {
+		if text, ok := part.(*StringLiteral); ok {
+			out.WriteString(text.Value)
+			continue
+		}
+		out.WriteString("${")
+		out.WriteString(part.String())
+		out.WriteString("}")
+	}
+	out.WriteByte('"')
+	return out.String()
+}
+
 type ListExpression struct {
 	Token *token.Token
 	Items []Expression
@@ -290,6 +395,8 @@ type IndexExpression struct {
 	Token *token.Token
 	Left  Expression
 	Index Expression
+	// Optional is set for Left?[Index]
+	Optional bool
 }
 
 func (ie *IndexExpression) expressionNode()      {}
@@ -299,6 +406,9 @@ func (ie *IndexExpression) String() string {
 
 	out.WriteByte('(')
 	out.WriteString(ie.Left.String())
+	if ie.Optional {
+		out.WriteByte('?')
+	}
 	out.WriteByte('[')
 	out.WriteString(ie.Index.String())
 	out.WriteByte(']')
@@ -306,14 +416,45 @@ func (ie *IndexExpression) String() string {
 	return out.String()
 }
 
-type mapEntry struct {
-	key   string
-	value string
+// SliceExpression is Left[Start:End:Step]. Bounds that were omitted are
+// nil.
+type SliceExpression struct {
+	Token *token.Token
+	Left  Expression
+	Start Expression
+	End   Expression
+	Step  Expression
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SliceExpression) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteByte('(')
+	out.WriteString(se.Left.String())
+	out.WriteByte('[')
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteByte(':')
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	if se.Step != nil {
+		out.WriteByte(':')
+		out.WriteString(se.Step.String())
+	}
+	out.WriteByte(']')
+	out.WriteByte(')')
+	return out.String()
 }
 
 type MapExpression struct {
 	Token   *token.Token
 	Entries map[Expression]Expression
+	// Keys holds the keys of Entries in the order they appear in the source
+	Keys []Expression
 }
 
 func (exp *MapExpression) expressionNode()      {}
@@ -321,18 +462,209 @@ func (exp *MapExpression) TokenLiteral() string { return exp.Token.Literal }
 func (exp *MapExpression) String() string {
 	out := new(bytes.Buffer)
 
-	pairs := make([]mapEntry, 0, len(exp.Entries))
-	for key, value := range exp.Entries {
-		pairs = append(pairs, mapEntry{key.String(), value.String()})
+	pairs := make([]string, 0, len(exp.Keys))
+	for _, key := range exp.Keys {
+		pairs = append(pairs, key.String()+": "+exp.Entries[key].String())
 	}
 
 	out.WriteByte('{')
-	for k, pair := range pairs {
-		out.WriteString(pair.key + ": " + pair.value)
-		if k < len(pairs) {
-			out.WriteString(", ")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteByte('}')
+	return out.String()
+}
+
+type ForStatement struct {
+	Token    *token.Token
+	Pattern  Pattern
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForStatement) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString("for (")
+	out.WriteString(fs.Pattern.String())
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(") { ")
+	out.WriteString(fs.Body.String())
+	out.WriteString(" }")
+	return out.String()
+}
+
+type AssignExpression struct {
+	Token  *token.Token
+	Target Expression // Identifier || IndexExpression || MemberExpression
+	Value  Expression
+}
+
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString("(")
+	out.WriteString(ae.Target.String())
+	out.WriteString(" = ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+	return out.String()
+}
+
+// YieldStatement suspends a generator, producing Value
+type YieldStatement struct {
+	Token *token.Token
+	Value Expression
+}
+
+func (ys *YieldStatement) statementNode()       {}
+func (ys *YieldStatement) TokenLiteral() string { return ys.Token.Literal }
+func (ys *YieldStatement) String() string {
+	return ys.TokenLiteral() + " " + ys.Value.String() + ";"
+}
+
+type ThrowStatement struct {
+	Token *token.Token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *ThrowStatement) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString(ts.TokenLiteral() + " ")
+	out.WriteString(ts.Value.String())
+	out.WriteString(";")
+	return out.String()
+}
+
+type TryStatement struct {
+	Token   *token.Token
+	Block   *BlockStatement
+	Catches []*CatchClause
+	Finally *BlockStatement
+}
+
+func (ts *TryStatement) statementNode()       {}
+func (ts *TryStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *TryStatement) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString("try { ")
+	out.WriteString(ts.Block.String())
+	out.WriteString(" }")
+	for _, clause := range ts.Catches {
+		out.WriteString(" ")
+		out.WriteString(clause.String())
+	}
+	if ts.Finally != nil {
+		out.WriteString(" finally { ")
+		out.WriteString(ts.Finally.String())
+		out.WriteString(" }")
+	}
+	return out.String()
+}
+
+// CatchClause handles errors raised in the block of a TryStatement. When
+// ErrorType is set only errors of that type are caught. Name is nil when
+// the error isn't bound.
+type CatchClause struct {
+	Token     *token.Token
+	ErrorType *Identifier
+	Name      *Identifier
+	Body      *BlockStatement
+}
+
+func (cc *CatchClause) TokenLiteral() string { return cc.Token.Literal }
+func (cc *CatchClause) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString("catch ")
+	if cc.Name != nil {
+		out.WriteString("(")
+		if cc.ErrorType != nil {
+			out.WriteString(cc.ErrorType.String() + " ")
 		}
+		out.WriteString(cc.Name.String())
+		out.WriteString(") ")
 	}
-	out.WriteByte('}')
+	out.WriteString("{ ")
+	out.WriteString(cc.Body.String())
+	out.WriteString(" }")
+	return out.String()
+}
+
+// DeferStatement defers Expression until the enclosing function returns.
+// When Expression is a call its function and arguments are evaluated by
+// the defer statement itself.
+type DeferStatement struct {
+	Token      *token.Token
+	Expression Expression
+}
+
+func (ds *DeferStatement) statementNode()       {}
+func (ds *DeferStatement) TokenLiteral() string { return ds.Token.Literal }
+func (ds *DeferStatement) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString(ds.TokenLiteral() + " ")
+	out.WriteString(ds.Expression.String())
+	out.WriteString(";")
+	return out.String()
+}
+
+type NullLiteral struct {
+	Token *token.Token
+}
+
+func (nl *NullLiteral) expressionNode()      {}
+func (nl *NullLiteral) TokenLiteral() string { return nl.Token.Literal }
+func (nl *NullLiteral) String() string       { return "null" }
+
+// MemberExpression is Object.Property, or Object?.Property when Optional
+// is set
+type MemberExpression struct {
+	Token    *token.Token
+	Object   Expression
+	Property *Identifier
+	Optional bool
+}
+
+func (me *MemberExpression) expressionNode()      {}
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MemberExpression) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteByte('(')
+	out.WriteString(me.Object.String())
+	if me.Optional {
+		out.WriteByte('?')
+	}
+	out.WriteByte('.')
+	out.WriteString(me.Property.String())
+	out.WriteByte(')')
+	return out.String()
+}
+
+type CoalesceExpression struct {
+	Token *token.Token
+	Left  Expression
+	Right Expression
+}
+
+func (ce *CoalesceExpression) expressionNode()      {}
+func (ce *CoalesceExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CoalesceExpression) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString("(")
+	out.WriteString(ce.Left.String())
+	out.WriteString(" ?? ")
+	out.WriteString(ce.Right.String())
+	out.WriteString(")")
 	return out.String()
 }
@@ -0,0 +1,80 @@
+package ast
+
+import (
+	"bytes"
+	"mitchlang/token"
+	"strings"
+)
+
+// ComprehensionClause is one `for pattern in iterable if condition` part
+// of a comprehension
+type ComprehensionClause struct {
+	Token      *token.Token
+	Pattern    Pattern
+	Iterable   Expression
+	Conditions []Expression
+}
+
+func (cc *ComprehensionClause) TokenLiteral() string { return cc.Token.Literal }
+func (cc *ComprehensionClause) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString("for ")
+	out.WriteString(cc.Pattern.String())
+	out.WriteString(" in ")
+	out.WriteString(cc.Iterable.String())
+	for _, condition := range cc.Conditions {
+		out.WriteString(" if ")
+		out.WriteString(condition.String())
+	}
+	return out.String()
+}
+
+func clausesString(clauses []*ComprehensionClause) string {
+	parts := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		parts = append(parts, clause.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+type ListComprehension struct {
+	Token   *token.Token
+	Element Expression
+	Clauses []*ComprehensionClause
+}
+
+func (lc *ListComprehension) expressionNode()      {}
+func (lc *ListComprehension) TokenLiteral() string { return lc.Token.Literal }
+func (lc *ListComprehension) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteByte('[')
+	out.WriteString(lc.Element.String())
+	out.WriteByte(' ')
+	out.WriteString(clausesString(lc.Clauses))
+	out.WriteByte(']')
+	return out.String()
+}
+
+type MapComprehension struct {
+	Token   *token.Token
+	Key     Expression
+	Value   Expression
+	Clauses []*ComprehensionClause
+}
+
+func (mc *MapComprehension) expressionNode()      {}
+func (mc *MapComprehension) TokenLiteral() string { return mc.Token.Literal }
+func (mc *MapComprehension) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteByte('{')
+	out.WriteString(mc.Key.String())
+	out.WriteString(": ")
+	out.WriteString(mc.Value.String())
+	out.WriteByte(' ')
+	out.WriteString(clausesString(mc.Clauses))
+	out.WriteByte('}')
+	return out.String()
+}
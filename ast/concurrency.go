@@ -0,0 +1,117 @@
+package ast
+
+import (
+	"bytes"
+	"mitchlang/token"
+	"strings"
+)
+
+// SpawnExpression runs Call on a new goroutine and evaluates to a task
+// that can be awaited for its result
+type SpawnExpression struct {
+	Token *token.Token
+	Call  *CallExpression
+}
+
+func (se *SpawnExpression) expressionNode()      {}
+func (se *SpawnExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpawnExpression) String() string {
+	return "(spawn " + se.Call.String() + ")"
+}
+
+// AwaitExpression waits for the task Value to finish and evaluates to its
+// result
+type AwaitExpression struct {
+	Token *token.Token
+	Value Expression
+}
+
+func (ae *AwaitExpression) expressionNode()      {}
+func (ae *AwaitExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AwaitExpression) String() string {
+	return "(await " + ae.Value.String() + ")"
+}
+
+// GroupStatement runs Block and then waits for every task spawned while it
+// ran. The first error raised by the block or a task cancels the others.
+type GroupStatement struct {
+	Token *token.Token
+	Block *BlockStatement
+}
+
+func (gs *GroupStatement) statementNode()       {}
+func (gs *GroupStatement) TokenLiteral() string { return gs.Token.Literal }
+func (gs *GroupStatement) String() string {
+	return "group { " + gs.Block.String() + " }"
+}
+
+// SelectExpression waits until one of its cases can proceed and evaluates
+// the body of that case
+type SelectExpression struct {
+	Token *token.Token
+	Cases []*SelectCase
+}
+
+func (se *SelectExpression) expressionNode()      {}
+func (se *SelectExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SelectExpression) String() string {
+	out := new(bytes.Buffer)
+
+	cases := make([]string, 0, len(se.Cases))
+	for _, c := range se.Cases {
+		cases = append(cases, c.String())
+	}
+
+	out.WriteString("select { ")
+	out.WriteString(strings.Join(cases, ", "))
+	out.WriteString(" }")
+	return out.String()
+}
+
+const (
+	SelectRecv    = "recv"
+	SelectSend    = "send"
+	SelectDefault = "default"
+)
+
+// SelectCase is one case of a select expression
+//
+//	name = recv(channel) => body
+//	send(channel, value) => body
+//	default => body
+type SelectCase struct {
+	Token *token.Token
+	// Kind is one of SelectRecv, SelectSend or SelectDefault
+	Kind    string
+	Name    *Identifier // set for receives that bind the value
+	Channel Expression
+	Value   Expression // set for sends
+	Body    Statement  // ExpressionStatement || BlockStatement
+}
+
+func (sc *SelectCase) TokenLiteral() string { return sc.Token.Literal }
+func (sc *SelectCase) String() string {
+	out := new(bytes.Buffer)
+
+	switch sc.Kind {
+	case SelectRecv:
+		if sc.Name != nil {
+			out.WriteString(sc.Name.String())
+			out.WriteString(" = ")
+		}
+		out.WriteString("recv(")
+		out.WriteString(sc.Channel.String())
+		out.WriteString(")")
+	case SelectSend:
+		out.WriteString("send(")
+		out.WriteString(sc.Channel.String())
+		out.WriteString(", ")
+		out.WriteString(sc.Value.String())
+		out.WriteString(")")
+	default:
+		out.WriteString(sc.Kind)
+	}
+	out.WriteString(" => ")
+	out.WriteString(sc.Body.String())
+	return out.String()
+}
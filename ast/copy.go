@@ -0,0 +1,81 @@
+package ast
+
+import (
+	"mitchlang/token"
+	"reflect"
+)
+
+// Copy returns a deep copy of the tree rooted at node, so that the copy
+// can be modified without changing node. Tokens are shared since they
+// are never modified.
+func Copy(node Node) Node {
+	if node == nil {
+		return nil
+	}
+	c := &copier{seen: map[pointer]reflect.Value{}}
+	return c.copy(reflect.ValueOf(node)).Interface().(Node)
+}
+
+var tokenType = reflect.TypeOf(&token.Token{})
+
+// pointer identifies a node that was already copied, so that a node
+// referenced twice, such as the keys of a MapExpression, is copied once
+type pointer struct {
+	t reflect.Type
+	p uintptr
+}
+
+type copier struct {
+	seen map[pointer]reflect.Value
+}
+
+func (c *copier) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || v.Type() == tokenType {
+			return v
+		}
+		key := pointer{v.Type(), v.Pointer()}
+		if out, ok := c.seen[key]; ok {
+			return out
+		}
+		out := reflect.New(v.Type().Elem())
+		c.seen[key] = out
+		out.Elem().Set(c.copy(v.Elem()))
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			out.Field(i).Set(c.copy(v.Field(i)))
+		}
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(c.copy(v.Elem()))
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(c.copy(iter.Key()), c.copy(iter.Value()))
+		}
+		return out
+	default:
+		return v
+	}
+}
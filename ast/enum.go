@@ -0,0 +1,51 @@
+package ast
+
+import (
+	"bytes"
+	"mitchlang/token"
+	"strings"
+)
+
+type EnumStatement struct {
+	Token    *token.Token
+	Name     *Identifier
+	Variants []*EnumVariant
+}
+
+func (es *EnumStatement) statementNode()       {}
+func (es *EnumStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *EnumStatement) String() string {
+	out := new(bytes.Buffer)
+
+	variants := make([]string, 0, len(es.Variants))
+	for _, variant := range es.Variants {
+		variants = append(variants, variant.String())
+	}
+
+	out.WriteString("enum ")
+	out.WriteString(es.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(variants, ", "))
+	out.WriteString(" }")
+	return out.String()
+}
+
+// EnumVariant is one case of an enum. Fields is nil for variants that
+// carry no payload.
+type EnumVariant struct {
+	Token  *token.Token
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (ev *EnumVariant) TokenLiteral() string { return ev.Token.Literal }
+func (ev *EnumVariant) String() string {
+	if ev.Fields == nil {
+		return ev.Name.String()
+	}
+	fields := make([]string, 0, len(ev.Fields))
+	for _, field := range ev.Fields {
+		fields = append(fields, field.String())
+	}
+	return ev.Name.String() + "(" + strings.Join(fields, ", ") + ")"
+}
@@ -0,0 +1,35 @@
+package ast
+
+import (
+	"bytes"
+	"mitchlang/token"
+	"strings"
+)
+
+// MacroLiteral is a macro definition. Macros are called with the AST of
+// their arguments and return the code that replaces the call.
+type MacroLiteral struct {
+	Token      *token.Token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (ml *MacroLiteral) expressionNode()      {}
+func (ml *MacroLiteral) TokenLiteral() string { return ml.Token.Literal }
+func (ml *MacroLiteral) String() string {
+	out := new(bytes.Buffer)
+
+	params := make([]string, 0, len(ml.Parameters))
+	for _, param := range ml.Parameters {
+		params = append(params, param.String())
+	}
+
+	out.WriteString(ml.TokenLiteral())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(")")
+	out.WriteString(" { ")
+	out.WriteString(ml.Body.String())
+	out.WriteString(" }")
+	return out.String()
+}
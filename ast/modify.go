@@ -0,0 +1,246 @@
+package ast
+
+// ModifierFunc returns the node that replaces node
+type ModifierFunc func(node Node) Node
+
+// Modify replaces each node of the tree rooted at node with the result of
+// modifier, starting from the leaves. A replacement must be usable in the
+// place of the node it replaces, so expressions are replaced by
+// expressions and statements by statements.
+//
+// Identifiers that don't refer to a binding, such as member names, struct
+// fields and the names of keyword arguments, aren't visited.
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch n := node.(type) {
+	case *Program:
+		for k, statement := range n.Statements {
+			n.Statements[k] = modifyStatement(statement, modifier)
+		}
+	case *BlockStatement:
+		for k, statement := range n.Statements {
+			n.Statements[k] = modifyStatement(statement, modifier)
+		}
+	case *ExpressionStatement:
+		n.Expression = modifyExpression(n.Expression, modifier)
+	case *LetStatement:
+		if n.Pattern != nil {
+			n.Pattern = modifyPattern(n.Pattern, modifier)
+		} else {
+			n.Name = modifyIdentifier(n.Name, modifier)
+		}
+		n.Value = modifyExpression(n.Value, modifier)
+	case *ReturnStatement:
+		n.ReturnValue = modifyExpression(n.ReturnValue, modifier)
+	case *YieldStatement:
+		n.Value = modifyExpression(n.Value, modifier)
+	case *ThrowStatement:
+		n.Value = modifyExpression(n.Value, modifier)
+	case *DeferStatement:
+		n.Expression = modifyExpression(n.Expression, modifier)
+	case *ForStatement:
+		n.Pattern = modifyPattern(n.Pattern, modifier)
+		n.Iterable = modifyExpression(n.Iterable, modifier)
+		n.Body = modifyBlock(n.Body, modifier)
+	case *TryStatement:
+		n.Block = modifyBlock(n.Block, modifier)
+		for _, clause := range n.Catches {
+			clause.Name = modifyIdentifier(clause.Name, modifier)
+			clause.Body = modifyBlock(clause.Body, modifier)
+		}
+		n.Finally = modifyBlock(n.Finally, modifier)
+	case *GroupStatement:
+		n.Block = modifyBlock(n.Block, modifier)
+	case *StructStatement:
+		n.Name = modifyIdentifier(n.Name, modifier)
+		for _, method := range n.Methods {
+			method.Function = modifyFunction(method.Function, modifier)
+		}
+	case *EnumStatement:
+		n.Name = modifyIdentifier(n.Name, modifier)
+	case *ExportStatement:
+		n.Statement = modifyStatement(n.Statement, modifier)
+	case *PrefixExpression:
+		n.Right = modifyExpression(n.Right, modifier)
+	case *PostfixExpression:
+		n.Left = modifyExpression(n.Left, modifier)
+	case *InfixExpression:
+		n.Left = modifyExpression(n.Left, modifier)
+		n.Right = modifyExpression(n.Right, modifier)
+	case *CoalesceExpression:
+		n.Left = modifyExpression(n.Left, modifier)
+		n.Right = modifyExpression(n.Right, modifier)
+	case *RangeExpression:
+		n.Start = modifyExpression(n.Start, modifier)
+		n.End = modifyExpression(n.End, modifier)
+		n.Step = modifyExpression(n.Step, modifier)
+	case *AssignExpression:
+		n.Target = modifyExpression(n.Target, modifier)
+		n.Value = modifyExpression(n.Value, modifier)
+	case *IfExpression:
+		n.Condition = modifyExpression(n.Condition, modifier)
+		n.Consequence = modifyBlock(n.Consequence, modifier)
+		n.Alternative = modifyBlock(n.Alternative, modifier)
+	case *FunctionLiteralExpression:
+		modifyFunction(n, modifier)
+	case *MacroLiteral:
+		for k, param := range n.Parameters {
+			n.Parameters[k] = modifyIdentifier(param, modifier)
+		}
+		n.Body = modifyBlock(n.Body, modifier)
+	case *CallExpression:
+		n.Function = modifyExpression(n.Function, modifier)
+		for k, arg := range n.Arguments {
+			n.Arguments[k] = modifyExpression(arg, modifier)
+		}
+		for _, kw := range n.Keywords {
+			kw.Value = modifyExpression(kw.Value, modifier)
+		}
+	case *InterpolatedString:
+		for k, part := range n.Parts {
+			n.Parts[k] = modifyExpression(part, modifier)
+		}
+	case *ListExpression:
+		for k, item := range n.Items {
+			n.Items[k] = modifyExpression(item, modifier)
+		}
+	case *MapExpression:
+		entries := make(map[Expression]Expression, len(n.Entries))
+		for k, key := range n.Keys {
+			value := modifyExpression(n.Entries[key], modifier)
+			n.Keys[k] = modifyExpression(key, modifier)
+			entries[n.Keys[k]] = value
+		}
+		n.Entries = entries
+	case *IndexExpression:
+		n.Left = modifyExpression(n.Left, modifier)
+		n.Index = modifyExpression(n.Index, modifier)
+	case *SliceExpression:
+		n.Left = modifyExpression(n.Left, modifier)
+		n.Start = modifyExpression(n.Start, modifier)
+		n.End = modifyExpression(n.End, modifier)
+		n.Step = modifyExpression(n.Step, modifier)
+	case *MemberExpression:
+		n.Object = modifyExpression(n.Object, modifier)
+	case *ListComprehension:
+		modifyClauses(n.Clauses, modifier)
+		n.Element = modifyExpression(n.Element, modifier)
+	case *MapComprehension:
+		modifyClauses(n.Clauses, modifier)
+		n.Key = modifyExpression(n.Key, modifier)
+		n.Value = modifyExpression(n.Value, modifier)
+	case *MatchExpression:
+		n.Subject = modifyExpression(n.Subject, modifier)
+		for _, arm := range n.Arms {
+			arm.Pattern = modifyPattern(arm.Pattern, modifier)
+			arm.Guard = modifyExpression(arm.Guard, modifier)
+			arm.Body = modifyStatement(arm.Body, modifier)
+		}
+	case *SpawnExpression:
+		if call, ok := modifyExpression(n.Call, modifier).(*CallExpression); ok {
+			n.Call = call
+		}
+	case *AwaitExpression:
+		n.Value = modifyExpression(n.Value, modifier)
+	case *SelectExpression:
+		for _, c := range n.Cases {
+			c.Name = modifyIdentifier(c.Name, modifier)
+			c.Channel = modifyExpression(c.Channel, modifier)
+			c.Value = modifyExpression(c.Value, modifier)
+			c.Body = modifyStatement(c.Body, modifier)
+		}
+	case *BindingPattern:
+		n.Name = modifyIdentifier(n.Name, modifier)
+	case *LiteralPattern:
+		n.Value = modifyExpression(n.Value, modifier)
+	case *ListPattern:
+		for k, element := range n.Elements {
+			n.Elements[k] = modifyPattern(element, modifier)
+		}
+		n.Rest = modifyPattern(n.Rest, modifier)
+	case *MapPattern:
+		for k, value := range n.Values {
+			n.Values[k] = modifyPattern(value, modifier)
+		}
+	case *VariantPattern:
+		for k, field := range n.Fields {
+			n.Fields[k] = modifyPattern(field, modifier)
+		}
+	}
+	return modifier(node)
+}
+
+// the helpers below modify a child of a node, keeping the original child
+// when it is nil or the replacement has the wrong type
+
+func modifyStatement(statement Statement, modifier ModifierFunc) Statement {
+	if statement == nil {
+		return nil
+	}
+	if out, ok := Modify(statement, modifier).(Statement); ok {
+		return out
+	}
+	return statement
+}
+
+func modifyExpression(expression Expression, modifier ModifierFunc) Expression {
+	if expression == nil {
+		return nil
+	}
+	if out, ok := Modify(expression, modifier).(Expression); ok {
+		return out
+	}
+	return expression
+}
+
+func modifyPattern(pattern Pattern, modifier ModifierFunc) Pattern {
+	if pattern == nil {
+		return nil
+	}
+	if out, ok := Modify(pattern, modifier).(Pattern); ok {
+		return out
+	}
+	return pattern
+}
+
+func modifyIdentifier(ident *Identifier, modifier ModifierFunc) *Identifier {
+	if ident == nil {
+		return nil
+	}
+	if out, ok := Modify(ident, modifier).(*Identifier); ok {
+		return out
+	}
+	return ident
+}
+
+func modifyBlock(block *BlockStatement, modifier ModifierFunc) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	if out, ok := Modify(block, modifier).(*BlockStatement); ok {
+		return out
+	}
+	return block
+}
+
+func modifyFunction(fn *FunctionLiteralExpression, modifier ModifierFunc) *FunctionLiteralExpression {
+	for k, param := range fn.Parameters {
+		// destructured parameters are visited through their pattern
+		if k < len(fn.Patterns) && fn.Patterns[k] != nil {
+			fn.Patterns[k] = modifyPattern(fn.Patterns[k], modifier)
+			continue
+		}
+		fn.Parameters[k] = modifyIdentifier(param, modifier)
+	}
+	fn.Body = modifyBlock(fn.Body, modifier)
+	return fn
+}
+
+func modifyClauses(clauses []*ComprehensionClause, modifier ModifierFunc) {
+	for _, clause := range clauses {
+		clause.Iterable = modifyExpression(clause.Iterable, modifier)
+		clause.Pattern = modifyPattern(clause.Pattern, modifier)
+		for k, condition := range clause.Conditions {
+			clause.Conditions[k] = modifyExpression(condition, modifier)
+		}
+	}
+}
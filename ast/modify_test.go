@@ -0,0 +1,88 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"mitchlang/token"
+)
+
+func one() Expression { return &IntegerLiteral{Token: token.New(token.Int, '1'), Value: 1} }
+func two() Expression { return &IntegerLiteral{Token: token.New(token.Int, '2'), Value: 2} }
+
+// turnOneIntoTwo replaces each literal 1 with 2
+func turnOneIntoTwo(node Node) Node {
+	integer, ok := node.(*IntegerLiteral)
+	if !ok || integer.Value != 1 {
+		return node
+	}
+	return two()
+}
+
+func TestModify(t *testing.T) {
+	block := func(e Expression) *BlockStatement {
+		return &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: e}}}
+	}
+	ident := func(name string) *Identifier {
+		return &Identifier{Token: token.NewFromString(token.Ident, name), Value: name}
+	}
+	key := one()
+	tests := []struct {
+		input    Node
+		expected string
+	}{
+		{one(), "2"},
+		{&Program{Statements: []Statement{&ExpressionStatement{Expression: one()}}}, "2"},
+		{&InfixExpression{Operator: "+", Left: one(), Right: two()}, "(2 + 2)"},
+		{&PrefixExpression{Operator: "-", Right: one()}, "(-2)"},
+		{&IndexExpression{Left: one(), Index: one()}, "(2[2])"},
+		{&IfExpression{Condition: one(), Consequence: block(one()), Alternative: block(one())}, "if2 2else 2"},
+		{&ReturnStatement{Token: token.NewFromString(token.Return, "return"), ReturnValue: one()}, "return 2;"},
+		{&LetStatement{Token: token.NewFromString(token.Let, "let"), Name: ident("x"), Value: one()}, "let x = 2;"},
+		{&FunctionLiteralExpression{Token: token.NewFromString(token.Function, "fn"), Body: block(one())}, "fn() { 2 }"},
+		{&ListExpression{Items: []Expression{one(), one()}}, "[2, 2]"},
+		{&InterpolatedString{Parts: []Expression{&StringLiteral{Value: "n="}, one()}}, `"n=${2}"`},
+		{&MapExpression{Keys: []Expression{key}, Entries: map[Expression]Expression{key: one()}}, "{2: 2}"},
+		{&CallExpression{Function: ident("f"), Arguments: []Expression{one()}, Keywords: []*KeywordArgument{{Name: ident("n"), Value: one()}}}, "f(2, n = 2)"},
+		{&MemberExpression{Object: one(), Property: ident("one")}, "(2.one)"},
+		{&MatchExpression{Subject: one(), Arms: []*MatchArm{{Pattern: &LiteralPattern{Value: one()}, Body: &ExpressionStatement{Expression: one()}}}}, "match (2) { 2 => 2 }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			require.Equal(t, tt.expected, Modify(tt.input, turnOneIntoTwo).String())
+		})
+	}
+}
+
+func TestModify_SkipsMemberNames(t *testing.T) {
+	node := &MemberExpression{
+		Object:   &Identifier{Value: "a"},
+		Property: &Identifier{Value: "a"},
+	}
+	Modify(node, func(node Node) Node {
+		if ident, ok := node.(*Identifier); ok {
+			return &Identifier{Value: ident.Value + "2"}
+		}
+		return node
+	})
+	require.Equal(t, "(a2.a)", node.String())
+}
+
+func TestCopy(t *testing.T) {
+	key := one()
+	node := &CallExpression{
+		Function: &Identifier{Value: "f"},
+		Arguments: []Expression{
+			&InfixExpression{Operator: "+", Left: one(), Right: one()},
+			&MapExpression{Keys: []Expression{key}, Entries: map[Expression]Expression{key: one()}},
+		},
+	}
+	copied := Copy(node)
+	require.Equal(t, node.String(), copied.String())
+
+	Modify(copied, turnOneIntoTwo)
+	require.Equal(t, "f((1 + 1), {1: 1})", node.String())
+	require.Equal(t, "f((2 + 2), {2: 2})", copied.String())
+	require.Nil(t, Copy(nil))
+}
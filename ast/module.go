@@ -0,0 +1,41 @@
+package ast
+
+import (
+	"bytes"
+	"mitchlang/token"
+)
+
+// ImportStatement binds the module at Path to Alias
+//
+//	import "lib/strings" as s;
+type ImportStatement struct {
+	Token *token.Token
+	Path  *StringLiteral
+	Alias *Identifier
+}
+
+func (is *ImportStatement) statementNode()       {}
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImportStatement) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString("import \"")
+	out.WriteString(is.Path.Value)
+	out.WriteString("\" as ")
+	out.WriteString(is.Alias.String())
+	out.WriteString(";")
+	return out.String()
+}
+
+// ExportStatement makes the names declared by Statement visible to
+// modules that import the enclosing module
+type ExportStatement struct {
+	Token     *token.Token
+	Statement Statement // LetStatement || StructStatement || EnumStatement
+}
+
+func (es *ExportStatement) statementNode()       {}
+func (es *ExportStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExportStatement) String() string {
+	return "export " + es.Statement.String()
+}
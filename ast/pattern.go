@@ -0,0 +1,171 @@
+package ast
+
+import (
+	"bytes"
+	"mitchlang/token"
+	"strings"
+)
+
+// Pattern is the left hand side of a match arm. Patterns are matched
+// against a value and may bind names in the scope of the arm.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+type MatchExpression struct {
+	Token   *token.Token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) String() string {
+	out := new(bytes.Buffer)
+
+	arms := make([]string, 0, len(me.Arms))
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match (")
+	out.WriteString(me.Subject.String())
+	out.WriteString(") { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+	return out.String()
+}
+
+type MatchArm struct {
+	Token   *token.Token
+	Pattern Pattern
+	Guard   Expression
+	Body    Statement // ExpressionStatement || BlockStatement
+}
+
+func (ma *MatchArm) TokenLiteral() string { return ma.Token.Literal }
+func (ma *MatchArm) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" if ")
+		out.WriteString(ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+	return out.String()
+}
+
+// WildcardPattern matches any value without binding it
+type WildcardPattern struct {
+	Token *token.Token
+}
+
+func (wp *WildcardPattern) patternNode()         {}
+func (wp *WildcardPattern) TokenLiteral() string { return wp.Token.Literal }
+func (wp *WildcardPattern) String() string       { return "_" }
+
+// BindingPattern matches any value and binds it to Name
+type BindingPattern struct {
+	Token *token.Token
+	Name  *Identifier
+}
+
+func (bp *BindingPattern) patternNode()         {}
+func (bp *BindingPattern) TokenLiteral() string { return bp.Token.Literal }
+func (bp *BindingPattern) String() string       { return bp.Name.String() }
+
+// LiteralPattern matches values equal to Value
+type LiteralPattern struct {
+	Token *token.Token
+	Value Expression
+}
+
+func (lp *LiteralPattern) patternNode()         {}
+func (lp *LiteralPattern) TokenLiteral() string { return lp.Token.Literal }
+func (lp *LiteralPattern) String() string       { return lp.Value.String() }
+
+// ListPattern matches lists element by element. When Rest is set the
+// list may be longer than Elements and the remainder is matched against
+// Rest.
+type ListPattern struct {
+	Token    *token.Token
+	Elements []Pattern
+	Rest     Pattern
+}
+
+func (lp *ListPattern) patternNode()         {}
+func (lp *ListPattern) TokenLiteral() string { return lp.Token.Literal }
+func (lp *ListPattern) String() string {
+	out := new(bytes.Buffer)
+
+	elements := make([]string, 0, len(lp.Elements)+1)
+	for _, el := range lp.Elements {
+		elements = append(elements, el.String())
+	}
+	if lp.Rest != nil {
+		elements = append(elements, "..."+lp.Rest.String())
+	}
+
+	out.WriteByte('[')
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteByte(']')
+	return out.String()
+}
+
+// MapPattern matches maps containing every key in Keys, matching the
+// value stored under each key against the pattern at the same position
+// in Values. Extra keys in the map are ignored.
+type MapPattern struct {
+	Token  *token.Token
+	Keys   []Expression
+	Values []Pattern
+}
+
+func (mp *MapPattern) patternNode()         {}
+func (mp *MapPattern) TokenLiteral() string { return mp.Token.Literal }
+func (mp *MapPattern) String() string {
+	out := new(bytes.Buffer)
+
+	pairs := make([]string, 0, len(mp.Keys))
+	for k := range mp.Keys {
+		pairs = append(pairs, mp.Keys[k].String()+": "+mp.Values[k].String())
+	}
+
+	out.WriteByte('{')
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteByte('}')
+	return out.String()
+}
+
+// VariantPattern matches values of an enum variant, matching each payload
+// value against the pattern at the same position in Fields. When Fields is
+// nil the payload isn't inspected.
+type VariantPattern struct {
+	Token   *token.Token
+	Enum    *Identifier
+	Variant *Identifier
+	Fields  []Pattern
+}
+
+func (vp *VariantPattern) patternNode()         {}
+func (vp *VariantPattern) TokenLiteral() string { return vp.Token.Literal }
+func (vp *VariantPattern) String() string {
+	out := new(bytes.Buffer)
+
+	out.WriteString(vp.Enum.String())
+	out.WriteByte('.')
+	out.WriteString(vp.Variant.String())
+	if vp.Fields != nil {
+		fields := make([]string, 0, len(vp.Fields))
+		for _, field := range vp.Fields {
+			fields = append(fields, field.String())
+		}
+		out.WriteByte('(')
+		out.WriteString(strings.Join(fields, ", "))
+		out.WriteByte(')')
+	}
+	return out.String()
+}
@@ -0,0 +1,63 @@
+package ast
+
+import (
+	"bytes"
+	"mitchlang/token"
+	"strings"
+)
+
+type StructStatement struct {
+	Token   *token.Token
+	Name    *Identifier
+	Fields  []*Identifier
+	Methods []*MethodDeclaration
+}
+
+func (ss *StructStatement) statementNode()       {}
+func (ss *StructStatement) TokenLiteral() string { return ss.Token.Literal }
+func (ss *StructStatement) String() string {
+	out := new(bytes.Buffer)
+
+	fields := make([]string, 0, len(ss.Fields))
+	for _, field := range ss.Fields {
+		fields = append(fields, field.String())
+	}
+
+	out.WriteString("struct ")
+	out.WriteString(ss.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(fields, ", "))
+	for _, method := range ss.Methods {
+		out.WriteString("; ")
+		out.WriteString(method.String())
+	}
+	out.WriteString(" }")
+	return out.String()
+}
+
+// MethodDeclaration is a named function declared in the body of a type.
+// The receiver is bound to self when the method is called.
+type MethodDeclaration struct {
+	Token    *token.Token
+	Name     *Identifier
+	Function *FunctionLiteralExpression
+}
+
+func (md *MethodDeclaration) TokenLiteral() string { return md.Token.Literal }
+func (md *MethodDeclaration) String() string {
+	out := new(bytes.Buffer)
+
+	params := make([]string, 0, len(md.Function.Parameters))
+	for _, param := range md.Function.Parameters {
+		params = append(params, param.String())
+	}
+
+	out.WriteString("fn ")
+	out.WriteString(md.Name.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") { ")
+	out.WriteString(md.Function.Body.String())
+	out.WriteString(" }")
+	return out.String()
+}
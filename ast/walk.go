@@ -0,0 +1,279 @@
+package ast
+
+import "fmt"
+
+// Visitor is called for each node found by Walk. If the visitor w returned
+// by Visit is not nil, Walk visits each child of the node with w, followed
+// by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree rooted at node depth first and in source order.
+// It starts by calling v.Visit(node), which must not be nil. Unlike
+// Modify every node is visited, including clauses such as MatchArm and
+// identifiers that name members and fields.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Identifier, *IntegerLiteral, *StringLiteral, *Boolean, *NullLiteral, *WildcardPattern:
+		// leaves
+
+	case *Program:
+		walkStatements(v, n.Statements)
+	case *BlockStatement:
+		walkStatements(v, n.Statements)
+	case *ExpressionStatement:
+		if n.Expression != nil {
+			Walk(v, n.Expression)
+		}
+	case *LetStatement:
+		if n.Pattern != nil {
+			Walk(v, n.Pattern)
+		} else {
+			Walk(v, n.Name)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *ReturnStatement:
+		if n.ReturnValue != nil {
+			Walk(v, n.ReturnValue)
+		}
+	case *YieldStatement:
+		Walk(v, n.Value)
+	case *ThrowStatement:
+		Walk(v, n.Value)
+	case *DeferStatement:
+		Walk(v, n.Expression)
+	case *ForStatement:
+		Walk(v, n.Pattern)
+		Walk(v, n.Iterable)
+		Walk(v, n.Body)
+	case *TryStatement:
+		Walk(v, n.Block)
+		for _, clause := range n.Catches {
+			Walk(v, clause)
+		}
+		if n.Finally != nil {
+			Walk(v, n.Finally)
+		}
+	case *CatchClause:
+		if n.ErrorType != nil {
+			Walk(v, n.ErrorType)
+		}
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		Walk(v, n.Body)
+	case *GroupStatement:
+		Walk(v, n.Block)
+	case *StructStatement:
+		Walk(v, n.Name)
+		walkIdentifiers(v, n.Fields)
+		for _, method := range n.Methods {
+			Walk(v, method)
+		}
+	case *MethodDeclaration:
+		Walk(v, n.Name)
+		Walk(v, n.Function)
+	case *EnumStatement:
+		Walk(v, n.Name)
+		for _, variant := range n.Variants {
+			Walk(v, variant)
+		}
+	case *EnumVariant:
+		Walk(v, n.Name)
+		walkIdentifiers(v, n.Fields)
+	case *ImportStatement:
+		Walk(v, n.Path)
+		if n.Alias != nil {
+			Walk(v, n.Alias)
+		}
+	case *ExportStatement:
+		Walk(v, n.Statement)
+
+	case *PrefixExpression:
+		Walk(v, n.Right)
+	case *PostfixExpression:
+		Walk(v, n.Left)
+	case *InfixExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *CoalesceExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *RangeExpression:
+		Walk(v, n.Start)
+		Walk(v, n.End)
+		if n.Step != nil {
+			Walk(v, n.Step)
+		}
+	case *AssignExpression:
+		Walk(v, n.Target)
+		Walk(v, n.Value)
+	case *IfExpression:
+		Walk(v, n.Condition)
+		Walk(v, n.Consequence)
+		if n.Alternative != nil {
+			Walk(v, n.Alternative)
+		}
+	case *FunctionLiteralExpression:
+		for k, param := range n.Parameters {
+			if k < len(n.Patterns) && n.Patterns[k] != nil {
+				Walk(v, n.Patterns[k])
+				continue
+			}
+			Walk(v, param)
+		}
+		Walk(v, n.Body)
+	case *MacroLiteral:
+		walkIdentifiers(v, n.Parameters)
+		Walk(v, n.Body)
+	case *CallExpression:
+		Walk(v, n.Function)
+		walkExpressions(v, n.Arguments)
+		for _, kw := range n.Keywords {
+			Walk(v, kw)
+		}
+	case *KeywordArgument:
+		Walk(v, n.Name)
+		Walk(v, n.Value)
+	case *InterpolatedString:
+		walkExpressions(v, n.Parts)
+	case *ListExpression:
+		walkExpressions(v, n.Items)
+	case *MapExpression:
+		for _, key := range n.Keys {
+			Walk(v, key)
+			Walk(v, n.Entries[key])
+		}
+	case *IndexExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Index)
+	case *SliceExpression:
+		Walk(v, n.Left)
+		if n.Start != nil {
+			Walk(v, n.Start)
+		}
+		if n.End != nil {
+			Walk(v, n.End)
+		}
+		if n.Step != nil {
+			Walk(v, n.Step)
+		}
+	case *MemberExpression:
+		Walk(v, n.Object)
+		Walk(v, n.Property)
+	case *ListComprehension:
+		Walk(v, n.Element)
+		for _, clause := range n.Clauses {
+			Walk(v, clause)
+		}
+	case *MapComprehension:
+		Walk(v, n.Key)
+		Walk(v, n.Value)
+		for _, clause := range n.Clauses {
+			Walk(v, clause)
+		}
+	case *ComprehensionClause:
+		Walk(v, n.Pattern)
+		Walk(v, n.Iterable)
+		walkExpressions(v, n.Conditions)
+	case *MatchExpression:
+		Walk(v, n.Subject)
+		for _, arm := range n.Arms {
+			Walk(v, arm)
+		}
+	case *MatchArm:
+		Walk(v, n.Pattern)
+		if n.Guard != nil {
+			Walk(v, n.Guard)
+		}
+		Walk(v, n.Body)
+	case *SpawnExpression:
+		Walk(v, n.Call)
+	case *AwaitExpression:
+		Walk(v, n.Value)
+	case *SelectExpression:
+		for _, c := range n.Cases {
+			Walk(v, c)
+		}
+	case *SelectCase:
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+		if n.Channel != nil {
+			Walk(v, n.Channel)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+		Walk(v, n.Body)
+
+	case *BindingPattern:
+		Walk(v, n.Name)
+	case *LiteralPattern:
+		Walk(v, n.Value)
+	case *ListPattern:
+		for _, element := range n.Elements {
+			Walk(v, element)
+		}
+		if n.Rest != nil {
+			Walk(v, n.Rest)
+		}
+	case *MapPattern:
+		for k, key := range n.Keys {
+			Walk(v, key)
+			Walk(v, n.Values[k])
+		}
+	case *VariantPattern:
+		Walk(v, n.Enum)
+		Walk(v, n.Variant)
+		for _, field := range n.Fields {
+			Walk(v, field)
+		}
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkStatements(v Visitor, statements []Statement) {
+	for _, statement := range statements {
+		Walk(v, statement)
+	}
+}
+
+func walkExpressions(v Visitor, expressions []Expression) {
+	for _, expression := range expressions {
+		Walk(v, expression)
+	}
+}
+
+func walkIdentifiers(v Visitor, identifiers []*Identifier) {
+	for _, ident := range identifiers {
+		Walk(v, ident)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the tree rooted at node in the same order as Walk,
+// calling f for each node. If f returns true, Inspect calls f for each
+// child of the node, followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
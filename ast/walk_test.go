@@ -0,0 +1,219 @@
+package ast_test
+
+import (
+	"fmt"
+	goast "go/ast"
+	goparser "go/parser"
+	gotoken "go/token"
+	"io/fs"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"mitchlang/ast"
+	"mitchlang/lexer"
+	"mitchlang/parser"
+)
+
+// everyNode is a program using every kind of node
+const everyNode = `import "lib" as l;
+export let a = 1;
+let [b, ...c] = [1, 2];
+const d = {"k": true};
+let f = fn(x, [y, z]) { return x; };
+let g = fn() { for (i in 0..10 step 2) { yield i; } };
+let m = macro(q) { return quote(unquote(q)); };
+struct P { x, y
+    fn get() { return self.x; }
+}
+enum Shape { Circle(r), Empty }
+try { throw "e"; } catch (ValueError e) { defer f(1); } finally { a = 2; }
+group { spawn f(1); }
+let t = await spawn f(2);
+select { v = recv(ch) => v, send(ch, 1) => { 1 }, default => 2 };
+match (a) { 1 => 1, [h, ...rest] if h > 0 => h, {"k": v} => v, Shape.Circle(r) => r, _ => null };
+[x * 2 for x in c if x > 1];
+{k: v for [k, v] in items(d)};
+f(a, n = 1)?;
+if (!d?.k ?? false) { c[1:2] } else { c[0] };
+"s ${-a}";
+`
+
+// nodeTypes returns the name of every type in the ast package that
+// implements Node, found by parsing the package source
+func nodeTypes(t *testing.T) map[string]bool {
+	fset := gotoken.NewFileSet()
+	notTest := func(info fs.FileInfo) bool { return !strings.HasSuffix(info.Name(), "_test.go") }
+	pkgs, err := goparser.ParseDir(fset, ".", notTest, 0)
+	require.NoError(t, err)
+
+	methods := map[string]map[string]bool{}
+	for _, file := range pkgs["ast"].Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*goast.FuncDecl)
+			if !ok || fn.Recv == nil {
+				continue
+			}
+			star, ok := fn.Recv.List[0].Type.(*goast.StarExpr)
+			if !ok {
+				continue
+			}
+			name := star.X.(*goast.Ident).Name
+			if methods[name] == nil {
+				methods[name] = map[string]bool{}
+			}
+			methods[name][fn.Name.Name] = true
+		}
+	}
+	types := map[string]bool{}
+	for name, m := range methods {
+		if m["TokenLiteral"] && m["String"] {
+			types[name] = true
+		}
+	}
+	require.NotEmpty(t, types)
+	return types
+}
+
+// walkCases returns the types handled by the type switch in Walk
+func walkCases(t *testing.T) map[string]bool {
+	fset := gotoken.NewFileSet()
+	file, err := goparser.ParseFile(fset, "walk.go", nil, 0)
+	require.NoError(t, err)
+
+	cases := map[string]bool{}
+	goast.Inspect(file, func(node goast.Node) bool {
+		clause, ok := node.(*goast.CaseClause)
+		if !ok {
+			return true
+		}
+		for _, exp := range clause.List {
+			if star, ok := exp.(*goast.StarExpr); ok {
+				cases[star.X.(*goast.Ident).Name] = true
+			}
+		}
+		return true
+	})
+	return cases
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestWalk_HandlesEveryNodeType(t *testing.T) {
+	cases := walkCases(t)
+	for _, name := range sortedKeys(nodeTypes(t)) {
+		require.True(t, cases[name], "node type %s is not handled by Walk", name)
+	}
+}
+
+func TestWalk_VisitsEveryNodeType(t *testing.T) {
+	p := parser.New(lexer.New(everyNode))
+	program := p.ParseProgram()
+	require.Empty(t, p.Errors())
+
+	visited := map[string]bool{}
+	ast.Inspect(program, func(node ast.Node) bool {
+		if node != nil {
+			visited[strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")] = true
+		}
+		return true
+	})
+	for _, name := range sortedKeys(nodeTypes(t)) {
+		require.True(t, visited[name], "node type %s is not visited, add it to everyNode", name)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	p := parser.New(lexer.New(`let x = a + 1; f(x)`))
+	program := p.ParseProgram()
+	require.Empty(t, p.Errors())
+
+	var visited []string
+	depth, maxDepth := 0, 0
+	ast.Inspect(program, func(node ast.Node) bool {
+		if node == nil {
+			depth--
+			return false
+		}
+		depth++
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		visited = append(visited, node.String())
+		return true
+	})
+	require.Equal(t, []string{
+		"let x = (a + 1);f(x)",
+		"let x = (a + 1);",
+		"x",
+		"(a + 1)",
+		"a",
+		"1",
+		"f(x)",
+		"f(x)",
+		"f",
+		"x",
+	}, visited)
+	require.Equal(t, 0, depth)
+	require.Equal(t, 4, maxDepth)
+}
+
+func TestInspect_SkipsChildren(t *testing.T) {
+	p := parser.New(lexer.New(`f(g(1), 2)`))
+	program := p.ParseProgram()
+	require.Empty(t, p.Errors())
+
+	var literals []string
+	ast.Inspect(program, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpression); ok && call.Function.String() == "g" {
+			return false
+		}
+		if literal, ok := node.(*ast.IntegerLiteral); ok {
+			literals = append(literals, literal.String())
+		}
+		return true
+	})
+	require.Equal(t, []string{"2"}, literals)
+}
+
+// counter counts the nodes it visits and stops at identifiers
+type counter map[string]int
+
+func (c counter) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		return nil
+	}
+	c[strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")]++
+	if _, ok := node.(*ast.Identifier); ok {
+		return nil
+	}
+	return c
+}
+
+func TestWalk(t *testing.T) {
+	p := parser.New(lexer.New(`let m = {"a": x, "b": y}; m["a"]`))
+	program := p.ParseProgram()
+	require.Empty(t, p.Errors())
+
+	c := counter{}
+	ast.Walk(c, program)
+	require.Equal(t, 1, c["MapExpression"])
+	require.Equal(t, 1, c["IndexExpression"])
+	require.Equal(t, 3, c["StringLiteral"])
+	require.Equal(t, 4, c["Identifier"])
+
+	require.Panics(t, func() { ast.Walk(c, unknownNode{}) })
+}
+
+type unknownNode struct{}
+
+func (unknownNode) TokenLiteral() string { return "" }
+func (unknownNode) String() string       { return "" }
@@ -0,0 +1,266 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/lexer"
+	"mitchlang/object"
+	"mitchlang/parser"
+	"mitchlang/token"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// codeModule builds the members of the code module, which makes scopes
+// for the eval builtin
+func codeModule() map[string]object.Object {
+	return map[string]object.Object{
+		"env": envBuiltin,
+	}
+}
+
+var (
+	evalBuiltin *object.Builtin
+	envBuiltin  = &object.Builtin{ContextFn: builtinEnv}
+)
+
+// importerKey is the context key of the importer of the module that
+// called eval or code.env
+type importerKey struct{}
+
+// callerImporter returns the importer of the module that called the
+// builtin running with ctx. Code that has no caller to take an importer
+// from, such as eval passed to parallel_map, can't import anything.
+func callerImporter(ctx context.Context) object.Importer {
+	if im, ok := ctx.Value(importerKey{}).(object.Importer); ok {
+		return im
+	}
+	return noImporter{}
+}
+
+// noImporter is an importer without any modules
+type noImporter struct{}
+
+func (noImporter) Import(path string, from *object.Module) object.Object {
+	return &object.Error{
+		ErrorType: object.ErrorTypeImportError,
+		Message:   fmt.Sprintf("module not found: %s", path),
+	}
+}
+
+// newScope returns the top level scope of a module with the importer of
+// the caller, in which evaluated code runs with ctx
+func newScope(ctx context.Context) *object.Env {
+	env := object.NewModule("eval", "", callerImporter(ctx)).Env
+	env.SetContext(ctx)
+	return env
+}
+
+// parseSource parses src, raising a SyntaxError when it doesn't parse
+func parseSource(src string) (*ast.Program, *object.Error) {
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		return nil, &object.Error{
+			ErrorType: object.ErrorTypeSyntaxError,
+			Message:   strings.Join(p.Errors(), "; "),
+		}
+	}
+	return program, nil
+}
+
+// sourceArg returns the source code passed to the builtin called name
+func sourceArg(name string, args []object.Object) (string, *object.Error) {
+	src, ok := args[0].(*object.String)
+	if !ok {
+		return "", object.NewTypeError(
+			"%s expected positional argument 1 to be type %s but received type %s",
+			name, object.TypeString, args[0].Type(),
+		)
+	}
+	return src.Value, nil
+}
+
+// builtinEval runs source code and returns the value of its last
+// statement. The code runs in a new scope holding only the builtins, which
+// imports modules with the importer of the caller, unless an Env made by code.env() to run it in, or a Map of names to
+// bind in the new scope, is passed as well. Errors raised by the code are
+// raised by eval.
+func builtinEval(ctx context.Context, kwargs map[string]object.Object, args ...object.Object) object.Object {
+	if err := noKeywords(kwargs); err != nil {
+		return err
+	}
+	if len(args) < 1 || len(args) > 2 {
+		return object.NewTypeError("eval expected 1 to 2 positional arguments but received %d", len(args))
+	}
+	src, err := sourceArg("eval", args)
+	if err != nil {
+		return err
+	}
+	program, err := parseSource(src)
+	if err != nil {
+		return err
+	}
+
+	env := newScope(ctx)
+	if len(args) == 2 {
+		switch scope := args[1].(type) {
+		case *object.Env:
+			env = scope.View(ctx)
+		case *object.Map:
+			if err := bindNames("eval", env, scope); err != nil {
+				return err
+			}
+		default:
+			return object.NewTypeError(
+				"eval expected positional argument 2 to be type %s or %s but received type %s",
+				object.TypeEnv, object.TypeMap, args[1].Type(),
+			)
+		}
+	}
+
+	DefineMacros(program, env)
+	expanded, err := ExpandMacros(program, env)
+	if err != nil {
+		return err
+	}
+	out := Eval(expanded, env)
+	if out == nil {
+		return object.NullValue
+	}
+	return out
+}
+
+// builtinEnv returns a new scope holding only the builtins, and the names
+// in the Map passed to it, which keeps the names defined by the code run
+// in it by eval() between calls
+func builtinEnv(ctx context.Context, kwargs map[string]object.Object, args ...object.Object) object.Object {
+	if err := noKeywords(kwargs); err != nil {
+		return err
+	}
+	if len(args) > 1 {
+		return object.NewTypeError("code.env expected 0 to 1 positional arguments but received %d", len(args))
+	}
+	env := object.NewModule("eval", "", callerImporter(ctx)).Env
+	if len(args) == 1 {
+		scope, ok := args[0].(*object.Map)
+		if !ok {
+			return object.NewTypeError(
+				"code.env expected positional argument 1 to be type %s but received type %s",
+				object.TypeMap, args[0].Type(),
+			)
+		}
+		if err := bindNames("code.env", env, scope); err != nil {
+			return err
+		}
+	}
+	return env
+}
+
+// bindNames binds the str keys of scope to their values in env
+func bindNames(name string, env *object.Env, scope *object.Map) *object.Error {
+	for _, pair := range scope.Pairs() {
+		key, ok := pair.Key.(*object.String)
+		if !ok {
+			return object.NewTypeError("%s expected names of type %s but received type %s", name, object.TypeString, pair.Key.Type())
+		}
+		env.Set(key.Value, pair.Value)
+	}
+	return nil
+}
+
+// builtinParse returns the syntax tree of source code as data. Each node
+// is a Map holding the name of its type under "type" and its children
+// under the names of their fields in snake case.
+func builtinParse(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return object.NewTypeError("parse expected 1 positional arguments but received %d", len(args))
+	}
+	src, err := sourceArg("parse", args)
+	if err != nil {
+		return err
+	}
+	program, err := parseSource(src)
+	if err != nil {
+		return err
+	}
+	return nodeData(reflect.ValueOf(program))
+}
+
+var tokenType = reflect.TypeOf(&token.Token{})
+
+// nodeData converts part of a syntax tree into objects
+func nodeData(v reflect.Value) object.Object {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return object.NullValue
+		}
+		if exp, ok := v.Interface().(*ast.MapExpression); ok {
+			return mapExpressionData(exp)
+		}
+		return nodeData(v.Elem())
+	case reflect.Struct:
+		m := object.NewMap()
+		m.Set(&object.String{Value: "type"}, &object.String{Value: v.Type().Name()})
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Type == tokenType {
+				continue
+			}
+			m.Set(&object.String{Value: snakeCase(field.Name)}, nodeData(v.Field(i)))
+		}
+		return m
+	case reflect.Slice:
+		values := make([]object.Object, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, nodeData(v.Index(i)))
+		}
+		return object.NewList(values...)
+	case reflect.String:
+		return &object.String{Value: v.String()}
+	case reflect.Int64:
+		return &object.Integer{Value: v.Int()}
+	case reflect.Bool:
+		if v.Bool() {
+			return object.True
+		}
+		return object.False
+	default:
+		return object.NullValue
+	}
+}
+
+// mapExpressionData lists the entries of a map literal as [key, value]
+// pairs, in the order they were written
+func mapExpressionData(exp *ast.MapExpression) object.Object {
+	values := make([]object.Object, 0, len(exp.Keys))
+	for _, key := range exp.Keys {
+		values = append(values, object.NewList(
+			nodeData(reflect.ValueOf(key)),
+			nodeData(reflect.ValueOf(exp.Entries[key])),
+		))
+	}
+	entries := object.NewList(values...)
+	m := object.NewMap()
+	m.Set(&object.String{Value: "type"}, &object.String{Value: "MapExpression"})
+	m.Set(&object.String{Value: "entries"}, entries)
+	return m
+}
+
+// snakeCase turns a field name such as ReturnValue into return_value
+func snakeCase(name string) string {
+	out := new(strings.Builder)
+	for k, r := range name {
+		if unicode.IsUpper(r) {
+			if k > 0 {
+				out.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
@@ -0,0 +1,117 @@
+package eval
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"mitchlang/lexer"
+	"mitchlang/object"
+	"mitchlang/parser"
+)
+
+func TestEval_EvalBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`eval("1 + 2")`, 3},
+		{`eval("")`, nil},
+		{`eval("let x = 2; x * x")`, 4},
+		{`eval("len([1, 2])")`, 2},
+		{`let x = 5; eval("x")`, "identifier not found: x"},
+		{`eval("x + y", {"x": 1, "y": 2})`, 3},
+		{`let f = fn(n) { return n * 10; }; eval("f(x)", {"f": f, "x": 4})`, 40},
+		{`let scope = {"x": 1}; eval("let y = 2; x", scope); scope`, "<map>"},
+		{`eval("let = ;")`, "expected next token"},
+		{`eval("[1][5]")`, "index out of range"},
+		{`eval("1", {1: 2})`, "eval expected names of type str but received type int"},
+		{`eval("1", [])`, "eval expected positional argument 2 to be type Env or Map but received type List"},
+		{`eval(1)`, "eval expected positional argument 1 to be type str but received type int"},
+		{`eval()`, "eval expected 1 to 2 positional arguments but received 0"},
+		{`let m = ""; try { eval("1 +"); } catch (SyntaxError e) { m = e.type; }; m`, "SyntaxError"},
+		{`let m = ""; try { eval("f(1"); } catch (SyntaxError e) { m = e.message; }; m`, "unterminated argument list"},
+		{`eval("1 +")`, "unexpected end of input"},
+		{`eval("1 /* 2")`, "unterminated block comment"},
+		{`let m = ""; try { eval("\\"); } catch (SyntaxError e) { m = e.type; }; m`, "SyntaxError"},
+		{`let m = ""; try { eval("[1][5]"); } catch (IndexError e) { m = "caught"; }; m`, "caught"},
+		{`eval("let twice = macro(x) { return quote(unquote(x) * 2); }; twice(4)")`, 8},
+		{`eval("eval(code)", {"code": "7"})`, 7},
+		{`let env = code.env(); eval("let n = 2", env); eval("n * 3", env)`, 6},
+		{`let env = code.env({"limit": 10}); eval("let doubled = limit * 2", env); eval("doubled + 1", env)`, 21},
+		{`let env = code.env(); eval("let n = 2", env); eval("n")`, "identifier not found: n"},
+		{`let env = code.env(); eval("env", env)`, "identifier not found: env"},
+		{`code.env([])`, "env expected positional argument 1 to be type Map but received type List"},
+		{`code.env({1: 2})`, "env expected names of type str but received type int"},
+		{`code.env({}, {})`, "env expected 0 to 1 positional arguments but received 2"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			if subtest.expected == "<map>" {
+				require.Equal(t, `{"x": 1}`, obj.Inspect())
+				return
+			}
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_EvalInEnv(t *testing.T) {
+	env := object.NewEnv()
+	env.Set("limit", &object.Integer{Value: 10})
+	scope := object.NewEnv()
+	scope.Set("rules", env)
+
+	p := parser.New(lexer.New(`eval("let doubled = limit * 2; doubled", rules)`))
+	out := Eval(p.ParseProgram(), scope)
+	testResult(t, out, 20)
+	// code run in a provided env defines names in it
+	doubled, ok := env.Get("doubled")
+	require.True(t, ok)
+	testResult(t, doubled, 20)
+	require.Equal(t, "<env>", env.Inspect())
+}
+
+// TestEval_EvalInEnvCancelled checks that code run in a scope made by
+// code.env() runs with the ctx of the caller
+func TestEval_EvalInEnvCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	p := parser.New(lexer.New(`let env = code.env(); eval("for (x in 0..1000000000) {}", env)`))
+	obj := EvalContext(ctx, p.ParseProgram(), object.NewEnv())
+	err, ok := obj.(*object.Error)
+	require.True(t, ok)
+	require.Equal(t, object.ErrorType(object.ErrorTypeCancelled), err.ErrorType)
+}
+
+func TestEval_ParseBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`parse("1")`, `{"type": "Program", "statements": [{"type": "ExpressionStatement", "expression": {"type": "IntegerLiteral", "value": 1}}]}`},
+		{`parse("x + 1")["statements"][0]["expression"]["operator"]`, `"+"`},
+		{`parse("x + 1")["statements"][0]["expression"]["left"]`, `{"type": "Identifier", "value": "x"}`},
+		{`parse("return x;")["statements"][0]["return_value"]["value"]`, `"x"`},
+		{`parse("let f = fn(a) { a };")["statements"][0]["value"]["parameters"]`, `[{"type": "Identifier", "value": "a"}]`},
+		{`parse("{1: true}")["statements"][0]["expression"]`, `{"type": "MapExpression", "entries": [[{"type": "IntegerLiteral", "value": 1}, {"type": "Boolean", "value": true}]]}`},
+		{`parse("if (x) { 1 }")["statements"][0]["expression"]["alternative"]`, `null`},
+		{`parse("let = 1;")`, `expected next token`},
+		{`parse("a \\ b")`, `illegal character '\\'`},
+		{`parse(1)`, `parse expected positional argument 1 to be type str but received type int`},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			if err, ok := obj.(*object.Error); ok {
+				require.Contains(t, err.Message, subtest.expected)
+				return
+			}
+			require.Equal(t, subtest.expected, obj.Inspect())
+		})
+	}
+}
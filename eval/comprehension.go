@@ -0,0 +1,99 @@
+package eval
+
+import (
+	"mitchlang/ast"
+	"mitchlang/object"
+)
+
+func evalListComprehension(n *ast.ListComprehension, env *object.Env) object.Object {
+	items := make([]object.Object, 0)
+	out := evalComprehensionClauses(n.Clauses, env.Push(), func(scope *object.Env) object.Object {
+		item := Eval(n.Element, scope)
+		if isUnwinding(item) {
+			return item
+		}
+		items = append(items, item)
+		return nil
+	})
+	if out != nil {
+		return out
+	}
+	return object.NewList(items...)
+}
+
+func evalMapComprehension(n *ast.MapComprehension, env *object.Env) object.Object {
+	m := object.NewMap()
+	out := evalComprehensionClauses(n.Clauses, env.Push(), func(scope *object.Env) object.Object {
+		key := Eval(n.Key, scope)
+		if isUnwinding(key) {
+			return key
+		}
+		value := Eval(n.Value, scope)
+		if isUnwinding(value) {
+			return value
+		}
+		if out := m.Set(key, value); isError(out) {
+			return out
+		}
+		return nil
+	})
+	if out != nil {
+		return out
+	}
+	return m
+}
+
+// evalComprehensionClauses calls emit once for each combination of values
+// produced by clauses that passes their conditions. The loop variables
+// are bound in scope, which is private to the comprehension. A non-nil
+// result stops evaluation and is returned.
+func evalComprehensionClauses(
+	clauses []*ast.ComprehensionClause,
+	scope *object.Env,
+	emit func(*object.Env) object.Object,
+) object.Object {
+	if len(clauses) == 0 {
+		return emit(scope)
+	}
+	clause := clauses[0]
+	iterable := Eval(clause.Iterable, scope)
+	if isUnwinding(iterable) {
+		return iterable
+	}
+	it, err := object.Iter(scope.Context(), iterable)
+	if err != nil {
+		return err
+	}
+	for {
+		value, ok := it.Next()
+		if !ok {
+			return nil
+		}
+		if err := scope.Context().Err(); err != nil {
+			return object.NewCancelledError(err)
+		}
+		if err, ok := value.(*object.Error); ok {
+			return err
+		}
+		if err := destructure(clause.Pattern, value, scope); err != nil {
+			return err
+		}
+		passed := true
+		for _, condition := range clause.Conditions {
+			out := Eval(condition, scope)
+			if isUnwinding(out) {
+				return out
+			}
+			if out != object.True {
+				passed = false
+				break
+			}
+		}
+		if !passed {
+			continue
+		}
+		if out := evalComprehensionClauses(clauses[1:], scope, emit); out != nil {
+			return out
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package eval
+
+import (
+	"context"
+	"mitchlang/ast"
+	"mitchlang/object"
+	"reflect"
+	"sync"
+)
+
+// groupKey is the context key of the innermost task group
+type groupKey struct{}
+
+// taskGroup tracks the tasks spawned inside a group statement
+type taskGroup struct {
+	wg     sync.WaitGroup
+	once   sync.Once
+	cancel context.CancelFunc
+	err    object.Object
+}
+
+// done marks a task of the group as finished. The first error cancels the
+// rest of the group.
+func (g *taskGroup) done(out object.Object) {
+	if isError(out) {
+		g.fail(out)
+	}
+	g.wg.Done()
+}
+
+func (g *taskGroup) fail(err object.Object) {
+	g.once.Do(func() {
+		g.err = err
+		g.cancel()
+	})
+}
+
+// evalGroupStatement runs the block of the group and waits for every task
+// spawned inside it, including tasks spawned by those tasks
+func evalGroupStatement(n *ast.GroupStatement, env *object.Env) object.Object {
+	ctx, cancel := context.WithCancel(env.Context())
+	defer cancel()
+	group := &taskGroup{cancel: cancel}
+	ctx = context.WithValue(ctx, groupKey{}, group)
+
+	out := Eval(n.Block, env.WithContext(ctx))
+	if isError(out) {
+		group.fail(out)
+	}
+	group.wg.Wait()
+	if group.err != nil {
+		return group.err
+	}
+	if _, ok := out.(*object.ReturnValue); ok {
+		return out
+	}
+	return object.NullValue
+}
+
+// evalSpawnExpression evaluates the function and arguments of the call
+// before starting it on a new goroutine
+func evalSpawnExpression(n *ast.SpawnExpression, env *object.Env) object.Object {
+	fn := Eval(n.Call.Function, env)
+	if isUnwinding(fn) {
+		return fn
+	}
+	args, kwargs, err := evalArguments(n.Call, env)
+	if err != nil {
+		return err
+	}
+	ctx := env.Context()
+	group, _ := ctx.Value(groupKey{}).(*taskGroup)
+	if group != nil {
+		group.wg.Add(1)
+	}
+	return object.Spawn(func() object.Object {
+		out := applyFunction(ctx, fn, args, kwargs)
+		if err, ok := out.(*object.Error); ok {
+			err.Trace = append(err.Trace, n.String())
+		}
+		if group != nil {
+			group.done(out)
+		}
+		return out
+	})
+}
+
+func evalSelectExpression(n *ast.SelectExpression, env *object.Env) object.Object {
+	cases := make([]reflect.SelectCase, 0, len(n.Cases))
+	for _, c := range n.Cases {
+		if c.Kind == ast.SelectDefault {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+			continue
+		}
+		obj := Eval(c.Channel, env)
+		if isUnwinding(obj) {
+			return obj
+		}
+		channel, ok := obj.(*object.Channel)
+		if !ok {
+			return object.NewTypeError("cannot %s on %s", c.Kind, obj.Type())
+		}
+		selectCase := reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(channel.Chan()),
+		}
+		if c.Kind == ast.SelectSend {
+			value := Eval(c.Value, env)
+			if isUnwinding(value) {
+				return value
+			}
+			selectCase.Dir = reflect.SelectSend
+			selectCase.Send = reflect.ValueOf(&value).Elem()
+		}
+		cases = append(cases, selectCase)
+	}
+	// the last case stops the select when the context is cancelled
+	ctx := env.Context()
+	cases = append(cases, reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(ctx.Done()),
+	})
+
+	chosen, received, ok, err := trySelect(cases)
+	if err != nil {
+		return err
+	}
+	if chosen == len(n.Cases) {
+		return object.NewCancelledError(ctx.Err())
+	}
+	c := n.Cases[chosen]
+	caseEnv := env.Push()
+	if c.Name != nil {
+		var value object.Object = object.NullValue
+		if ok {
+			value = received.Interface().(object.Object)
+		}
+		caseEnv.Set(c.Name.Value, value)
+	}
+	return Eval(c.Body, caseEnv)
+}
+
+// trySelect runs reflect.Select, turning a send on a closed channel into an
+// error
+func trySelect(cases []reflect.SelectCase) (chosen int, received reflect.Value, ok bool, err *object.Error) {
+	defer func() {
+		if recover() != nil {
+			err = &object.Error{
+				ErrorType: object.ErrorTypeValueError,
+				Message:   "send on closed channel",
+			}
+		}
+	}()
+	chosen, received, ok = reflect.Select(cases)
+	return chosen, received, ok, nil
+}
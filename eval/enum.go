@@ -0,0 +1,79 @@
+package eval
+
+import (
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/object"
+)
+
+func evalEnumStatement(n *ast.EnumStatement, env *object.Env) object.Object {
+	enum := &object.EnumType{Name: n.Name.Value}
+	for _, variant := range n.Variants {
+		if _, ok := enum.Variant(variant.Name.Value); ok {
+			return &object.Error{
+				Message: fmt.Sprintf("duplicate variant %s in enum %s", variant.Name, enum.Name),
+			}
+		}
+		var fields []string
+		if variant.Fields != nil {
+			fields = make([]string, 0, len(variant.Fields))
+			for _, field := range variant.Fields {
+				fields = append(fields, field.Value)
+			}
+		}
+		enum.AddVariant(variant.Name.Value, fields)
+	}
+	if out := env.Set(enum.Name, enum); isError(out) {
+		return out
+	}
+	return object.NullValue
+}
+
+func bindVariantPattern(
+	p *ast.VariantPattern,
+	value object.Object,
+	b *bindings,
+	env *object.Env,
+) *object.Error {
+	obj := Eval(p.Enum, env)
+	if err, ok := obj.(*object.Error); ok {
+		return b.fail(err)
+	}
+	enum, ok := obj.(*object.EnumType)
+	if !ok {
+		return b.fail(object.NewTypeError("%s is not an enum", p.Enum))
+	}
+	variant, ok := enum.Variant(p.Variant.Value)
+	if !ok {
+		return b.fail(&object.Error{
+			ErrorType: object.ErrorTypeKeyError,
+			Message:   fmt.Sprintf("%s has no member %s", enum.Name, p.Variant),
+		})
+	}
+	ev, ok := value.(*object.EnumValue)
+	if !ok || ev.Variant != variant {
+		return &object.Error{
+			ErrorType: object.ErrorTypeValueError,
+			Message:   fmt.Sprintf("expected %s, got %s", p, value.Inspect()),
+		}
+	}
+	if p.Fields == nil {
+		return nil
+	}
+	if len(p.Fields) != len(ev.Values) {
+		return &object.Error{
+			ErrorType: object.ErrorTypeValueError,
+			Message: fmt.Sprintf(
+				"expected %d values to unpack, got %d",
+				len(p.Fields),
+				len(ev.Values),
+			),
+		}
+	}
+	for k, field := range p.Fields {
+		if err := bindPattern(field, ev.Values[k], b, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package eval
 
 import (
+	"context"
 	"fmt"
 	"mitchlang/ast"
 	"mitchlang/object"
@@ -8,11 +9,30 @@ import (
 )
 
 var builtins = map[string]*object.Builtin{
-	"len":   {Fn: object.BuiltinLen},
-	"add":   {Fn: object.BuiltinAdd},
-	"exit":  {Fn: object.BuiltinExit},
-	"list":  {Fn: object.BuiltinList},
-	"print": {Fn: object.BuiltinPrintln},
+	"len":          {ContextFn: object.BuiltinLen},
+	"add":          {ContextFn: object.BuiltinAdd},
+	"exit":         {Fn: object.BuiltinExit},
+	"list":         {ContextFn: object.BuiltinList},
+	"print":        {ContextFn: object.BuiltinPrintln},
+	"freeze":       {Fn: object.BuiltinFreeze},
+	"error":        {Fn: object.BuiltinError},
+	"is_error":     {Fn: object.BuiltinIsError},
+	"unwrap_or":    {Fn: object.BuiltinUnwrapOr},
+	"items":        {Fn: object.BuiltinItems},
+	"str":          {ContextFn: object.BuiltinStr},
+	"next":         {Fn: object.BuiltinNext},
+	"channel":      {Fn: object.BuiltinChannel},
+	"parallel_map": {ContextFn: object.BuiltinParallelMap},
+	"parse":        {Fn: builtinParse},
+	"help":         {Fn: object.BuiltinHelp},
+}
+
+// EvalContext evaluates node in env, which from then on runs with ctx.
+// Loops, function calls and blocking builtins stop with a Cancelled error
+// once ctx is cancelled.
+func EvalContext(ctx context.Context, node ast.Node, env *object.Env) object.Object {
+	env.SetContext(ctx)
+	return Eval(node, env)
 }
 
 func Eval(node ast.Node, env *object.Env) object.Object {
@@ -23,7 +43,7 @@ func Eval(node ast.Node, env *object.Env) object.Object {
 		return evalBlockStatements(n.Statements, env)
 	case *ast.IfExpression:
 		condition := Eval(n.Condition, env)
-		if isError(condition) {
+		if isUnwinding(condition) {
 			return condition
 		}
 		if condition == object.True {
@@ -36,23 +56,31 @@ func Eval(node ast.Node, env *object.Env) object.Object {
 		}
 	case *ast.InfixExpression:
 		left := Eval(n.Left, env)
-		if isError(left) {
+		if isUnwinding(left) {
 			return left
 		}
 		right := Eval(n.Right, env)
-		if isError(right) {
+		if isUnwinding(right) {
 			return right
 		}
-		return evalInfixIntegerExpression(n.Operator, left, right)
+		return evalInfixIntegerExpression(env.Context(), n.Operator, left, right)
 	case *ast.ExpressionStatement:
 		return Eval(n.Expression, env)
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: n.Value}
 	case *ast.StringLiteral:
 		return &object.String{Value: n.Value}
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(n, env)
+	case *ast.PostfixExpression:
+		left := Eval(n.Left, env)
+		if isUnwinding(left) {
+			return left
+		}
+		return evalPostfixOperator(n.Operator, left)
 	case *ast.PrefixExpression:
 		right := Eval(n.Right, env)
-		if isError(right) {
+		if isUnwinding(right) {
 			return right
 		}
 		return evalPrefixOperator(n.Operator, right)
@@ -62,14 +90,87 @@ func Eval(node ast.Node, env *object.Env) object.Object {
 		}
 		return object.False
 	case *ast.ReturnStatement:
-		return &object.ReturnValue{Value: Eval(n.ReturnValue, env)}
+		value := Eval(n.ReturnValue, env)
+		if isUnwinding(value) {
+			return value
+		}
+		return &object.ReturnValue{Value: value}
 	case *ast.LetStatement:
 		obj := Eval(n.Value, env)
-		if isError(obj) {
+		if isUnwinding(obj) {
 			return obj
 		}
-		env.Set(n.Name.Value, obj)
+		if n.Pattern != nil {
+			if err := destructureWith(n.Pattern, obj, env, n.IsConst()); err != nil {
+				return err
+			}
+			return object.NullValue
+		}
+		declare := env.Set
+		if n.IsConst() {
+			declare = env.SetConst
+		}
+		if out := declare(n.Name.Value, obj); isError(out) {
+			return out
+		}
+		return object.NullValue
+	case *ast.AssignExpression:
+		return evalAssignExpression(n, env)
+	case *ast.ForStatement:
+		return evalForStatement(n, env)
+	case *ast.GroupStatement:
+		return evalGroupStatement(n, env)
+	case *ast.StructStatement:
+		return evalStructStatement(n, env)
+	case *ast.EnumStatement:
+		return evalEnumStatement(n, env)
+	case *ast.ImportStatement:
+		return evalImportStatement(n, env)
+	case *ast.ExportStatement:
+		return evalExportStatement(n, env)
+	case *ast.DeferStatement:
+		frame := env.Frame()
+		if frame == nil {
+			return &object.Error{Message: "defer outside of a function"}
+		}
+		call, ok := n.Expression.(*ast.CallExpression)
+		if !ok || isCallOf(call, quoteName) {
+			// other expressions are evaluated when the function returns,
+			// in the scope the defer statement appeared in
+			frame.Defer(func() object.Object { return Eval(n.Expression, env) })
+			return object.NullValue
+		}
+		// as in Go the function and its arguments are evaluated now and
+		// the call is made when the function returns
+		obj, args, kwargs, err := evalCallee(call, env)
+		if err != nil {
+			return endChain(err)
+		}
+		frame.Defer(func() object.Object { return callFunction(call, env, obj, args, kwargs) })
 		return object.NullValue
+	case *ast.YieldStatement:
+		value := Eval(n.Value, env)
+		if isUnwinding(value) {
+			return value
+		}
+		frame := env.Frame()
+		if frame == nil || !frame.IsGenerator() {
+			return &object.Error{Message: "yield outside of a generator"}
+		}
+		if !frame.Yield(value) {
+			// the generator was closed, so unwind the call running
+			// deferred functions and finally blocks on the way out
+			return &object.ReturnValue{Value: object.NullValue}
+		}
+		return object.NullValue
+	case *ast.ThrowStatement:
+		value := Eval(n.Value, env)
+		if isUnwinding(value) {
+			return value
+		}
+		return evalThrow(value)
+	case *ast.TryStatement:
+		return evalTryStatement(n, env)
 	case *ast.Identifier:
 		if obj, ok := env.Get(n.Value); ok {
 			return obj
@@ -77,6 +178,12 @@ func Eval(node ast.Node, env *object.Env) object.Object {
 		if obj, ok := builtins[n.Value]; ok {
 			return obj
 		}
+		// native modules can be used without importing them
+		if im, ok := importerFor(env).(*Importer); ok {
+			if module, ok := im.native(n.Value); ok {
+				return module
+			}
+		}
 
 		return &object.Error{
 			Message: fmt.Sprintf("identifier not found: %s", n.Value),
@@ -84,89 +191,427 @@ func Eval(node ast.Node, env *object.Env) object.Object {
 	case *ast.FunctionLiteralExpression:
 		obj := &object.Function{
 			Parameters: n.Parameters,
+			Patterns:   n.Patterns,
 			Body:       n.Body,
 			Env:        env,
+			Generator:  n.Generator,
+			Doc:        n.Doc,
 		}
 		return obj
+	case *ast.MacroLiteral:
+		return &object.Error{Message: "macros can only be defined at the top level"}
 	case *ast.CallExpression:
-		obj := Eval(n.Function, env)
-		if isError(obj) {
-			return obj
-		}
-		args := make([]object.Object, 0, len(n.Arguments))
-		for _, exp := range n.Arguments {
-			out := Eval(exp, env)
-			if isError(out) {
-				return out
-			}
-			args = append(args, out)
-		}
-		switch fn := obj.(type) {
-		case *object.Builtin:
-			return fn.Fn(args...)
-		case *object.Function:
-			functionEnv := fn.Env.Push()
-			for k := range fn.Parameters {
-				functionEnv.Set(fn.Parameters[k].Value, args[k])
-			}
-			// Need to remove the variables from the environment
-			out := Eval(fn.Body, functionEnv)
-			if rv, ok := out.(*object.ReturnValue); ok {
-				return rv.Value
-			}
-			return out
-		default:
-			return &object.Error{Message: fmt.Sprintf("not a function %s", fn.Type())}
-		}
+		return endChain(evalCallExpression(n, env))
 	case *ast.ListExpression:
 		items := make([]object.Object, 0, len(n.Items))
 		for k := range n.Items {
 			item := Eval(n.Items[k], env)
-			if isError(item) {
+			if isUnwinding(item) {
 				return item
 			}
 			items = append(items, item)
 		}
-		return &object.List{Values: items}
+		return object.NewList(items...)
 	case *ast.IndexExpression:
-		items := Eval(n.Left, env)
-		if isError(items) {
-			return items
+		return endChain(evalIndex(n, env))
+	case *ast.MemberExpression:
+		return endChain(evalMemberExpression(n, env))
+	case *ast.NullLiteral:
+		return object.NullValue
+	case *ast.CoalesceExpression:
+		left := Eval(n.Left, env)
+		if isUnwinding(left) {
+			return left
 		}
-		rank := Eval(n.Index, env)
-		if isError(rank) {
-			return rank
+		if left != object.NullValue {
+			return left
 		}
-		integer, ok := rank.(*object.Integer)
+		return Eval(n.Right, env)
+	case *ast.RangeExpression:
+		return evalRangeExpression(n, env)
+	case *ast.SliceExpression:
+		return endChain(evalSliceExpression(n, env))
+	case *ast.MapExpression:
+		m := object.NewMap()
+		for _, key := range n.Keys {
+			k := Eval(key, env)
+			if isUnwinding(k) {
+				return k
+			}
+			v := Eval(n.Entries[key], env)
+			if isUnwinding(v) {
+				return v
+			}
+			if out := m.Set(k, v); isError(out) {
+				return out
+			}
+		}
+		return m
+	case *ast.ListComprehension:
+		return evalListComprehension(n, env)
+	case *ast.MapComprehension:
+		return evalMapComprehension(n, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(n, env)
+	case *ast.SpawnExpression:
+		return evalSpawnExpression(n, env)
+	case *ast.AwaitExpression:
+		value := Eval(n.Value, env)
+		if isUnwinding(value) {
+			return value
+		}
+		task, ok := value.(*object.Task)
 		if !ok {
-			return object.NewTypeError("expected integer, got %s", rank.Type())
+			return object.NewTypeError("cannot await %s", value.Type())
+		}
+		return task.Await(env.Context())
+	case *ast.SelectExpression:
+		return evalSelectExpression(n, env)
+	}
+	return nil
+}
+
+func init() {
+	object.Call = func(ctx context.Context, fn object.Object, args ...object.Object) object.Object {
+		return applyFunction(ctx, fn, args, nil)
+	}
+	// eval is registered here since it calls Eval, which reads builtins
+	evalBuiltin = &object.Builtin{ContextFn: builtinEval}
+	builtins["eval"] = evalBuiltin
+}
+
+// evalCallExpression evaluates a call, or returns the quoted code when
+// the call is to quote
+func evalCallExpression(n *ast.CallExpression, env *object.Env) object.Object {
+	if isCallOf(n, quoteName) {
+		return evalQuote(n, env)
+	}
+	obj, args, kwargs, err := evalCallee(n, env)
+	if err != nil {
+		return err
+	}
+	return callFunction(n, env, obj, args, kwargs)
+}
+
+// evalCallee evaluates the function and arguments of a call, returning
+// a non-nil err when one of them unwinds or the function is the end of a
+// short circuited chain
+func evalCallee(n *ast.CallExpression, env *object.Env) (
+	obj object.Object,
+	args []object.Object,
+	kwargs map[string]object.Object,
+	err object.Object,
+) {
+	obj = evalChain(n.Function, env)
+	if obj == skipChain || isUnwinding(obj) {
+		return nil, nil, nil, obj
+	}
+	args, kwargs, err = evalArguments(n, env)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return obj, args, kwargs, nil
+}
+
+// evalArguments evaluates the positional and keyword arguments of a call
+// in the order they are written, returning a non-nil err when one of them
+// unwinds
+func evalArguments(n *ast.CallExpression, env *object.Env) (
+	args []object.Object,
+	kwargs map[string]object.Object,
+	err object.Object,
+) {
+	args = make([]object.Object, 0, len(n.Arguments))
+	for _, exp := range n.Arguments {
+		out := Eval(exp, env)
+		if isUnwinding(out) {
+			return nil, nil, out
 		}
-		index := int(integer.Value)
-		length := int(object.BuiltinLen(items).(*object.Integer).Value)
-		if index < 0 {
-			index = length + index
+		args = append(args, out)
+	}
+	for _, kw := range n.Keywords {
+		out := Eval(kw.Value, env)
+		if isUnwinding(out) {
+			return nil, nil, out
+		}
+		if kwargs == nil {
+			kwargs = map[string]object.Object{}
+		}
+		if _, ok := kwargs[kw.Name.Value]; ok {
+			return nil, nil, object.NewTypeError("keyword argument %s repeated", kw.Name)
 		}
+		kwargs[kw.Name.Value] = out
+	}
+	return args, kwargs, nil
+}
 
-		if index >= length || index < 0 {
-			typeString := strings.ToLower(items.Type().String())
-			return &object.Error{
-				ErrorType: "IndexError",
-				Message:   fmt.Sprintf("%s index out of range", typeString),
+// callFunction makes the call n to obj, adding the call to the trace of
+// any error the function raises. Builtins that make scopes for code are
+// passed the importer of the caller.
+func callFunction(
+	n *ast.CallExpression,
+	env *object.Env,
+	obj object.Object,
+	args []object.Object,
+	kwargs map[string]object.Object,
+) object.Object {
+	ctx := env.Context()
+	if obj == evalBuiltin || obj == envBuiltin {
+		ctx = context.WithValue(ctx, importerKey{}, importerFor(env))
+	}
+	out := applyFunction(ctx, obj, args, kwargs)
+	if _, ok := obj.(*object.Function); ok {
+		if err, ok := out.(*object.Error); ok {
+			err.Trace = append(err.Trace, n.Function.String())
+		}
+	}
+	return out
+}
+
+// applyFunction calls obj, which may be a script function, a builtin or
+// a constructor, with args. The call is made with ctx and stops early if
+// it is cancelled.
+func applyFunction(
+	ctx context.Context,
+	obj object.Object,
+	args []object.Object,
+	kwargs map[string]object.Object,
+) object.Object {
+	if err := ctx.Err(); err != nil {
+		return object.NewCancelledError(err)
+	}
+	switch fn := obj.(type) {
+	case *object.Builtin:
+		return fn.Call(ctx, kwargs, args...)
+	case *object.StructType:
+		if err := noKeywords(kwargs); err != nil {
+			return err
+		}
+		return fn.New(args...)
+	case *object.Variant:
+		if err := noKeywords(kwargs); err != nil {
+			return err
+		}
+		return fn.New(args...)
+	case *object.Function:
+		args, err := functionArguments(fn, args, kwargs)
+		if err != nil {
+			return err
+		}
+		functionEnv := fn.Env.PushFrame(ctx)
+		if err := bindParameters(fn, args, functionEnv); err != nil {
+			return err
+		}
+		if fn.Generator {
+			return object.NewGenerator(functionEnv.Frame(), func() object.Object {
+				out := Eval(fn.Body, functionEnv)
+				out = functionEnv.Frame().RunDeferred(out)
+				if isError(out) {
+					return out
+				}
+				// the value returned by a generator is discarded
+				return object.NullValue
+			})
+		}
+		// Need to remove the variables from the environment
+		out := Eval(fn.Body, functionEnv)
+		out = functionEnv.Frame().RunDeferred(out)
+		if rv, ok := out.(*object.ReturnValue); ok {
+			return rv.Value
+		}
+		return out
+	default:
+		return &object.Error{Message: fmt.Sprintf("not a function %s", fn.Type())}
+	}
+}
+
+// functionArguments orders the arguments of a call to fn by parameter,
+// placing each keyword argument at the parameter of the same name
+func functionArguments(
+	fn *object.Function,
+	args []object.Object,
+	kwargs map[string]object.Object,
+) ([]object.Object, *object.Error) {
+	if len(kwargs) == 0 && len(args) >= len(fn.Parameters) {
+		return args, nil
+	}
+	bound := make([]object.Object, len(fn.Parameters))
+	copy(bound, args)
+	for name, value := range kwargs {
+		k := -1
+		for i, param := range fn.Parameters {
+			// destructured parameters can't be passed by name
+			if param.Value == name && (i >= len(fn.Patterns) || fn.Patterns[i] == nil) {
+				k = i
 			}
 		}
-		switch ob := items.(type) {
-		case *object.String:
-			return &object.String{Value: string(ob.Value[index])}
-		case *object.List:
-			return ob.Values[index]
-		default:
-			return object.NewTypeError("expected list or string, got %s", ob.Type())
+		if k < 0 {
+			return nil, object.NewTypeError("unexpected keyword argument %s", name)
+		}
+		if k < len(args) {
+			return nil, object.NewTypeError("multiple values for argument %s", name)
+		}
+		bound[k] = value
+	}
+	for k, value := range bound {
+		if value == nil {
+			return nil, object.NewTypeError("missing argument %s", fn.Parameters[k])
 		}
+	}
+	return bound, nil
+}
 
+func noKeywords(kwargs map[string]object.Object) *object.Error {
+	for name := range kwargs {
+		return object.NewTypeError("unexpected keyword argument %s", name)
 	}
 	return nil
 }
 
+func evalAssignExpression(n *ast.AssignExpression, env *object.Env) object.Object {
+	switch target := n.Target.(type) {
+	case *ast.Identifier:
+		value := Eval(n.Value, env)
+		if isUnwinding(value) {
+			return value
+		}
+		return env.Assign(target.Value, value)
+	case *ast.IndexExpression:
+		container := Eval(target.Left, env)
+		if isUnwinding(container) {
+			return container
+		}
+		index := Eval(target.Index, env)
+		if isUnwinding(index) {
+			return index
+		}
+		value := Eval(n.Value, env)
+		if isUnwinding(value) {
+			return value
+		}
+		return object.SetIndex(container, index, value)
+	case *ast.MemberExpression:
+		obj := Eval(target.Object, env)
+		if isUnwinding(obj) {
+			return obj
+		}
+		value := Eval(n.Value, env)
+		if isUnwinding(value) {
+			return value
+		}
+		return setMember(obj, target.Property.Value, value)
+	default:
+		return &object.Error{Message: fmt.Sprintf("cannot assign to %s", n.Target)}
+	}
+}
+
+func evalRangeExpression(n *ast.RangeExpression, env *object.Env) object.Object {
+	bounds := []int64{0, 0, 1}
+	for k, bound := range []ast.Expression{n.Start, n.End, n.Step} {
+		if bound == nil {
+			continue
+		}
+		obj := Eval(bound, env)
+		if isUnwinding(obj) {
+			return obj
+		}
+		integer, ok := obj.(*object.Integer)
+		if !ok {
+			return object.NewTypeError("range bounds must be int, got %s", obj.Type())
+		}
+		bounds[k] = integer.Value
+	}
+	return object.NewRange(bounds[0], bounds[1], bounds[2], n.Inclusive)
+}
+
+func evalSliceExpression(n *ast.SliceExpression, env *object.Env) object.Object {
+	items := evalChain(n.Left, env)
+	if items == skipChain || isUnwinding(items) {
+		return items
+	}
+	bounds := make([]object.Object, 3)
+	for k, bound := range []ast.Expression{n.Start, n.End, n.Step} {
+		if bound == nil {
+			continue
+		}
+		bounds[k] = Eval(bound, env)
+		if isUnwinding(bounds[k]) {
+			return bounds[k]
+		}
+	}
+	return object.Slice(items, bounds[0], bounds[1], bounds[2])
+}
+
+func evalIndexExpression(ctx context.Context, items object.Object, rank object.Object) object.Object {
+	switch container := items.(type) {
+	case *object.Map:
+		value, ok := container.Get(rank)
+		if !ok {
+			return &object.Error{
+				ErrorType: object.ErrorTypeKeyError,
+				Message:   fmt.Sprintf("key not found: %s", rank.Inspect()),
+			}
+		}
+		return value
+	case *object.Instance:
+		return container.IndexContext(ctx, rank)
+	case *object.ErrorValue:
+		value, ok := container.Get(rank)
+		if !ok {
+			return &object.Error{
+				ErrorType: object.ErrorTypeKeyError,
+				Message:   fmt.Sprintf("error has no field %s", rank.Inspect()),
+			}
+		}
+		return value
+	}
+	integer, ok := rank.(*object.Integer)
+	if !ok {
+		return object.NewTypeError("expected integer, got %s", rank.Type())
+	}
+	index := int(integer.Value)
+	length := int(object.BuiltinLen(ctx, nil, items).(*object.Integer).Value)
+	if index < 0 {
+		index = length + index
+	}
+
+	if index >= length || index < 0 {
+		typeString := strings.ToLower(items.Type().String())
+		return &object.Error{
+			ErrorType: object.ErrorTypeIndexError,
+			Message:   fmt.Sprintf("%s index out of range", typeString),
+		}
+	}
+	switch ob := items.(type) {
+	case *object.String:
+		return ob.At(index)
+	case *object.List:
+		return ob.At(index)
+	case *object.Range:
+		return ob.At(index)
+	default:
+		return object.NewTypeError("expected list or string, got %s", ob.Type())
+	}
+}
+
+// evalInterpolatedString joins the parts of the string, converting the
+// value of each embedded expression the same way as str()
+func evalInterpolatedString(n *ast.InterpolatedString, env *object.Env) object.Object {
+	out := new(strings.Builder)
+	for _, part := range n.Parts {
+		value := Eval(part, env)
+		if isUnwinding(value) {
+			return value
+		}
+		s := object.Str(env.Context(), value)
+		if isError(s) {
+			return s
+		}
+		out.WriteString(s.(*object.String).Value)
+	}
+	return &object.String{Value: out.String()}
+}
+
 func evalBangOperator(right object.Object) object.Object {
 	switch right {
 	case object.True:
@@ -200,7 +645,23 @@ func evalPrefixOperator(operator string, right object.Object) object.Object {
 	}
 }
 
+func evalPostfixOperator(operator string, left object.Object) object.Object {
+	switch operator {
+	case "?":
+		// error values are returned from the enclosing function
+		if _, ok := left.(*object.ErrorValue); ok {
+			return &object.ReturnValue{Value: left}
+		}
+		return left
+	default:
+		return &object.Error{
+			Message: fmt.Sprintf("unknown operator: %s%s", left.Type(), operator),
+		}
+	}
+}
+
 func evalInfixIntegerExpression(
+	ctx context.Context,
 	operator string,
 	left object.Object,
 	right object.Object,
@@ -223,11 +684,19 @@ func evalInfixIntegerExpression(
 		binaryFunc = object.Lt
 	case ">":
 		binaryFunc = object.Gt
+	case "in":
+		binaryFunc = object.In
+	case "not in":
+		binaryFunc = object.NotIn
+	case "is":
+		binaryFunc = object.Is
+	case "is not":
+		binaryFunc = object.IsNot
 	default:
 		return object.NullValue
 	}
 	if binaryFunc != nil {
-		val := binaryFunc(left, right)
+		val := binaryFunc(ctx, left, right)
 		if e, ok := val.(*object.Error); ok {
 			return e
 		}
@@ -273,3 +742,14 @@ func isError(obj object.Object) bool {
 	}
 	return false
 }
+
+// isUnwinding reports whether obj has to be handed straight back to the
+// caller instead of being used as a value: either a raised error, or a
+// return triggered from inside an expression by the ? operator
+func isUnwinding(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Error, *object.ReturnValue:
+		return true
+	}
+	return false
+}
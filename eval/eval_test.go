@@ -1,9 +1,11 @@
 package eval
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 
 	"mitchlang/lexer"
 	"mitchlang/object"
@@ -449,6 +451,8 @@ func testResult(t *testing.T, obj object.Object, expected interface{}) {
 		require.Equal(t, expected, obj.Value)
 	case *object.String:
 		require.Equal(t, expected, obj.Value)
+	case *object.Null:
+		require.Nil(t, expected)
 	case *object.Error:
 		require.Contains(t, obj.Message, expected)
 	case *object.List:
@@ -456,10 +460,1182 @@ func testResult(t *testing.T, obj object.Object, expected interface{}) {
 		if !ok {
 			require.FailNow(t, "expected should be a list")
 		}
-		for k := range obj.Values {
-			testResult(t, obj.Values[k], exp[k])
+		require.Len(t, obj.Values(), len(exp))
+		for k := range obj.Values() {
+			testResult(t, obj.Values()[k], exp[k])
 		}
 	default:
 		t.Fatalf("unknown type %T", obj)
 	}
 }
+
+func TestEval_MapExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{"a": 1, "b": 2}["b"]`, 2},
+		{`let m = {1: "one", true: "yes"}; m[true]`, "yes"},
+		{`len({"a": 1, "b": 2})`, 2},
+		{`{"a": 1}["c"]`, "key not found: \"c\""},
+		{`{[1]: 1}`, "unhashable type: List"},
+		{`list({"b": 1, "a": 2})`, []interface{}{"b", "a"}},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_MatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`match (1) { 1 => "one", 2 => "two" }`, "one"},
+		{`match (2) { 1 => "one", 2 => "two" }`, "two"},
+		{`match (-1) { -1 => "minus one", _ => "other" }`, "minus one"},
+		{`match ("b") { "a" => 1, _ => 2 }`, 2},
+		{`match (true) { false => 0, true => 1 }`, 1},
+		{`match (5) { x => x * 2 }`, 10},
+		{`match (5) { x if x > 10 => "big", x if x > 1 => "medium", _ => "small" }`, "medium"},
+		{`match ([1, 2, 3]) { [] => 0, [a] => a, [a, b] => a + b, _ => -1 }`, -1},
+		{`match ([1, 2]) { [] => 0, [a] => a, [a, b] => a + b, _ => -1 }`, 3},
+		{`match ([1, 2, 3]) { [first, ...rest] => rest }`, []interface{}{2, 3}},
+		{`match ([1]) { [first, ...rest] => len(rest) }`, 0},
+		{`match ([1, 2, 3]) { [1, ...] => "starts with one", _ => "other" }`, "starts with one"},
+		{`match ([[1, 2], 3]) { [[a, b], c] => a + b + c }`, 6},
+		{`match ({"name": "mitch", "age": 3}) { {"name": n} => n }`, "mitch"},
+		{`match ({"name": "mitch", "age": 3}) { {name, age} => age }`, 3},
+		{`match ({"age": 3}) { {"name": n} => n, {"age": 3} => "three" }`, "three"},
+		{`match (1) { 1 => { return 10; } }`, 10},
+		{`match (3) { 1 => "one", 2 => "two" }`, "no pattern matched value 3"},
+		{`match ("1") { 1 => "one" }`, "no pattern matched value \"1\""},
+		{`match (1) { x if y => x }`, "identifier not found: y"},
+		{`let x = 1; match (2) { x => x }; x`, 1},
+		{`match (1) { -true => 0, _ => 1 }`, "unknown operator: -bool"},
+		{`match ({"a": 1}) { {missing: v} => v, _ => 0 }`, "identifier not found: missing"},
+		{`match (1) { Nope.Variant => 0, _ => 1 }`, "identifier not found: Nope"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_MatchError(t *testing.T) {
+	obj := testParseInput(`match (3) { 1 => "one" }`)
+	require.IsType(t, &object.Error{}, obj)
+	require.Equal(t, object.ErrorType(object.ErrorTypeMatchError), obj.(*object.Error).ErrorType)
+}
+
+func TestEval_DestructuringLet(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let [a, b] = [1, 2]; a + b`, 3},
+		{`let [a, ...rest] = [1, 2, 3]; rest`, []interface{}{2, 3}},
+		{`let [_, [b, c]] = [1, [2, 3]]; b * c`, 6},
+		{`let {name, age} = {"name": "mitch", "age": 3}; name`, "mitch"},
+		{`let {"n": [x, y]} = {"n": [4, 5]}; x + y`, 9},
+		{`let [a, b] = [1, 2, 3]`, "expected 2 values to unpack, got 3"},
+		{`let [a, b, ...c] = [1]`, "expected at least 2 values to unpack, got 1"},
+		{`let [a] = 1`, "cannot destructure int as List"},
+		{`let {a} = [1]`, "cannot destructure List as Map"},
+		{`let {name} = {"age": 3}`, `key not found: "name"`},
+		{`let [a, a] = [1, 2]`, "name a bound more than once in pattern"},
+		{`let [a, {"b": a}] = [1, {"b": 2}]`, "name a bound more than once in pattern"},
+		{`match ([1, 2]) { [x, x] => x }`, "name x bound more than once in pattern"},
+		{`const b = 0; try { let [a, b, c] = [1, 2, 3]; } catch (e) {}; a`, "identifier not found: a"},
+		{`const b = 0; try { let [a, b, c] = [1, 2, 3]; } catch (e) {}; c`, "identifier not found: c"},
+		{`let a = 0; try { const [b, a] = [1, 2]; } catch (e) {}; b`, "identifier not found: b"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_DestructuringParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let f = fn([a, b], c) { return a + b + c; }; f([1, 2], 3)`, 6},
+		{`let f = fn({x, y}) { return x * y; }; f({"x": 2, "y": 4})`, 8},
+		{`let f = fn([a, b]) { return a; }; f([1])`, "expected 2 values to unpack, got 1"},
+		{`let f = fn({x}, {x}) { return x; }; f({"x": 1}, {"x": 2})`, "name x bound more than once in parameters"},
+		{`let f = fn(x, [x, y]) { return x; }; f(1, [2, 3])`, "name x bound more than once in parameters"},
+		{`let f = fn(a, a) { return a; }; f(1, 2)`, "name a bound more than once in parameters"},
+		{`let f = fn([a, a]) { return a; }; f([1, 2])`, "name a bound more than once in pattern"},
+		{`let f = fn({x}, [y, _], _) { return x + y; }; f({"x": 1}, [2, 3], 4)`, 3},
+		{`let f = fn(_, _) { return 1; }; f(2, 3)`, 1},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_ForStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let total = 0; for (x in [1, 2, 3]) { let total = total + x; } total`, 6},
+		{`let s = ""; for (c in "abc") { let s = c + s; } s`, "cba"},
+		{`let s = ""; for (k in {"a": 1, "b": 2}) { let s = s + k; } s`, "ab"},
+		{`let t = 0; for ([a, b] in [[1, 2], [3, 4]]) { let t = t + a * b; } t`, 14},
+		{`let f = fn(xs) { for (x in xs) { if (x > 1) { return x; } } return 0; }; f([1, 2, 3])`, 2},
+		{`for (x in 1) { x }`, "object is not iterable: int"},
+		{`for ([a, b] in [[1]]) { a }`, "expected 2 values to unpack, got 1"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_AssignExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let x = 1; x = 2; x`, 2},
+		{`let x = 1; let y = x = 5; [x, y]`, []interface{}{5, 5}},
+		{`let x = 1; let f = fn() { x = x + 1; }; f(); f(); x`, 3},
+		{`let total = 0; for (x in [1, 2, 3]) { total = total + x; } total`, 6},
+		{`let ok = 1 == 1; ok`, true},
+		{`let xs = [1, 2, 3]; xs[0] = 10; xs[-1] = 30; xs`, []interface{}{10, 2, 30}},
+		{`let m = {"a": 1}; m["b"] = 2; m["a"] = 3; [m["a"], m["b"]]`, []interface{}{3, 2}},
+		{`y = 1`, "identifier not found: y"},
+		{`let xs = [1]; xs[1] = 2`, "list assignment index out of range"},
+		{`let s = "abc"; s[0] = "b"`, "object does not support item assignment: str"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_ConstStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`const x = 1; x`, 1},
+		{`const [a, b] = [1, 2]; a + b`, 3},
+		{`const x = 1; x = 2`, "cannot assign to constant x"},
+		{`const x = 1; let f = fn() { x = 2; }; f()`, "cannot assign to constant x"},
+		{`const x = 1; let x = 2`, "cannot redeclare constant x"},
+		{`const x = 1; const x = 2`, "cannot redeclare x as constant"},
+		{`let x = 1; const x = 2`, "cannot redeclare x as constant"},
+		{`const [a, b] = [1, 2]; a = 3`, "cannot assign to constant a"},
+		{`const x = 1; for (x in [1]) { x }`, "cannot redeclare constant x"},
+		{`const x = 1; let f = fn() { let x = 2; return x; }; f()`, 2},
+		{`const x = 1; let f = fn(x) { return x; }; f(3)`, 3},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_Freeze(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let xs = freeze([1, 2]); xs[0]`, 1},
+		{`let xs = freeze([1, 2]); xs[0] = 3`, "cannot modify frozen List"},
+		{`let m = freeze({"a": 1}); m["a"] = 2`, "cannot modify frozen Map"},
+		{`let m = freeze({"a": [1]}); m["a"][0] = 2`, "cannot modify frozen List"},
+		{`let xs = freeze([{"a": 1}]); xs[0]["b"] = 2`, "cannot modify frozen Map"},
+		{`let xs = [1]; freeze(xs); xs[0] = 2`, "cannot modify frozen List"},
+		{`let xs = [1]; let ys = freeze([xs]); xs[0] = 2`, "cannot modify frozen List"},
+		{`freeze(1)`, 1},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+
+	obj := testParseInput(`let xs = freeze([1]); xs[0] = 2`)
+	require.Equal(t, object.ErrorType(object.ErrorTypeFrozenError), obj.(*object.Error).ErrorType)
+}
+
+func TestEval_TryStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let x = 0; try { x = 1; } catch (e) { x = 2; } x`, 1},
+		{`let x = 0; try { throw "boom"; x = 1; } catch (e) { x = 2; } x`, 2},
+		{`let m = ""; try { throw "boom"; } catch (e) { m = e["message"]; } m`, "boom"},
+		{`let m = ""; try { [1][5]; } catch (e) { m = e["type"]; } m`, "IndexError"},
+		{`let m = ""; try { missing; } catch (e) { m = e["type"]; } m`, "Exception"},
+		{`let m = ""; try { 1 + true; } catch (TypeError e) { m = "type"; } catch (e) { m = "other"; } m`, "type"},
+		{`let m = ""; try { [1][5]; } catch (TypeError e) { m = "type"; } catch (e) { m = "other"; } m`, "other"},
+		{`let m = ""; try { [1][5]; } catch (Exception e) { m = "caught"; } m`, "caught"},
+		{`try { [1][5]; } catch (TypeError e) { 1 }`, "list index out of range"},
+		{`let m = ""; try { throw error("bad", "ValueError"); } catch (ValueError e) { m = e["message"]; } m`, "bad"},
+		{`let x = 0; try { x = 1; } finally { x = x + 10; } x`, 11},
+		{`let x = 0; try { throw "a"; } catch { x = 1; } finally { x = x + 10; } x`, 11},
+		{`let x = 0; try { throw "a"; } finally { x = 1; }`, "a"},
+		{`let x = 0; let f = fn() { try { return 1; } finally { x = 5; } }; [f(), x]`, []interface{}{1, 5}},
+		{`let f = fn() { try { return 1; } finally { return 2; } }; f()`, 2},
+		{`try { throw "a"; } catch (e) { throw "b"; }`, "b"},
+		{`try { throw "a"; } catch (e) { throw e; }`, "a"},
+		{`let e = 5; try { throw "a"; } catch (e) {} e`, 5},
+		{`try { throw "a"; } catch (e) {} e`, "identifier not found: e"},
+		{`const e = 5; let m = ""; try { throw "a"; } catch (e) { m = e["message"]; } [m, e]`, []interface{}{"a", 5}},
+		{`throw 1`, "can only throw str or error, got int"},
+		{`let x = 0; try { throw "a"; } catch (e) { x = 1; } let y = x + 1; y`, 2},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			if ev, ok := obj.(*object.ErrorValue); ok {
+				require.Equal(t, subtest.expected, ev.Inspect())
+				return
+			}
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_ErrorTrace(t *testing.T) {
+	input := `
+let inner = fn() { throw "boom"; };
+let outer = fn() { inner(); };
+let trace = [];
+try { outer(); } catch (e) { trace = e["trace"]; }
+trace
+`
+	obj := testParseInput(input)
+	testResult(t, obj, []interface{}{"inner", "outer"})
+}
+
+// TestEval_ErrorTraceRethrow checks that throwing an error value again
+// doesn't add to the trace of the value. Run with -race to check tasks
+// throwing one error value don't share its trace.
+func TestEval_ErrorTraceRethrow(t *testing.T) {
+	obj := testParseInput(`let err = error("shared");
+let g = fn() { throw err; };
+let traces = [];
+for (i in 0..3) { try { g(); } catch (e) { traces.push(e["trace"]); } }
+let tasks = [spawn g() for i in 0..8];
+for (t in tasks) { try { await t; } catch (e) {} };
+traces.push(err["trace"]);
+traces`)
+	testResult(t, obj, []interface{}{
+		[]interface{}{"g"}, []interface{}{"g"}, []interface{}{"g"}, []interface{}{},
+	})
+}
+
+func TestEval_TryOperator(t *testing.T) {
+	validate := `
+let positive = fn(x) {
+  if (x < 0) {
+    return error("negative", "ValueError");
+  }
+  return x;
+};
+let double = fn(x) {
+  let y = positive(x)?;
+  return y * 2;
+};
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{validate + `double(2)`, 4},
+		{validate + `is_error(double(-2))`, true},
+		{validate + `is_error(double(2))`, false},
+		{validate + `double(-2)`, "ValueError: negative"},
+		{validate + `unwrap_or(double(-2), 0)`, 0},
+		{validate + `unwrap_or(double(3), 0)`, 6},
+		{validate + `let e = double(-2); let x = 1; e`, "ValueError: negative"},
+		{validate + `let f = fn() { return [positive(-1)?, 1]; }; is_error(f())`, true},
+		{validate + `let f = fn() { return positive(1)? + positive(2)?; }; f()`, 3},
+		{`let f = fn() { let x = 5?; return x; }; f()`, 5},
+		{`error("top")?; 1`, "Exception: top"},
+		{`let f = fn(x) { return x?; }; let g = fn() { f(error("e")); return 1; }; g()`, 1},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			if ev, ok := obj.(*object.ErrorValue); ok {
+				require.Equal(t, subtest.expected, ev.Inspect())
+				return
+			}
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_DeferStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let log = ""; let f = fn() { defer log = log + "a"; defer log = log + "b"; log = log + "c"; }; f(); log`, "cba"},
+		{`let log = ""; let f = fn() { defer log = log + "a"; return 1; log = log + "b"; }; [f(), log]`, []interface{}{1, "a"}},
+		{`let log = ""; let f = fn(x) { defer log = log + "d"; if (x) { return 1; } return 2; }; f(true); f(false); log`, "dd"},
+		{`let log = ""; let f = fn() { defer log = log + "a"; throw "boom"; }; try { f(); } catch (e) { log = log + e["message"]; } log`, "aboom"},
+		{`let log = ""; let f = fn() { defer log = log + "a"; [1][2]; }; try { f(); } catch (e) { log = log + e["type"]; } log`, "aIndexError"},
+		{`let boom = fn() { throw "deferred"; }; let f = fn() { defer boom(); return 1; }; f()`, "deferred"},
+		{`let boom = fn() { throw "deferred"; }; let f = fn() { defer boom(); throw "original"; }; f()`, "original"},
+		{`let log = ""; let boom = fn() { throw "b"; }; let f = fn() { defer log = log + "a"; defer boom(); defer log = log + "c"; }; try { f(); } catch (e) { log = log + e["message"]; } log`, "cab"},
+		{`let x = 1; let f = fn() { defer x = x * 10; x = x + 1; return x; }; [f(), x]`, []interface{}{2, 20}},
+		{`let log = ""; let f = fn() { defer log = log + "f"; let g = fn() { defer log = log + "g"; }; g(); log = log + "-"; }; f(); log`, "g-f"},
+		{`defer 1;`, "defer outside of a function"},
+		{`let log = []; let f = fn() { for (i in 0..3) { defer log.push(i); } }; f(); log`, []interface{}{2, 1, 0}},
+		{`let log = []; let f = fn() { let x = 1; defer log.push(x); x = 2; }; f(); log`, []interface{}{1}},
+		{`let log = []; let f = fn() { let g = fn(x) { log.push("first " + str(x)); }; defer g(1); g = fn(x) { log.push("second"); }; }; f(); log`, []interface{}{"first 1"}},
+		{`let log = ""; let f = fn() { let x = "a"; defer log = log + x; x = "b"; }; f(); log`, "b"},
+		{`let f = fn() { defer missing(1); return 1; }; f()`, "identifier not found: missing"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_SliceExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 2, 3, 4, 5][1:3]`, []interface{}{2, 3}},
+		{`[1, 2, 3, 4, 5][:2]`, []interface{}{1, 2}},
+		{`[1, 2, 3, 4, 5][3:]`, []interface{}{4, 5}},
+		{`[1, 2, 3, 4, 5][:]`, []interface{}{1, 2, 3, 4, 5}},
+		{`[1, 2, 3, 4, 5][::2]`, []interface{}{1, 3, 5}},
+		{`[1, 2, 3, 4, 5][1::2]`, []interface{}{2, 4}},
+		{`[1, 2, 3, 4, 5][-2:]`, []interface{}{4, 5}},
+		{`[1, 2, 3, 4, 5][:-2]`, []interface{}{1, 2, 3}},
+		{`[1, 2, 3, 4, 5][::-1]`, []interface{}{5, 4, 3, 2, 1}},
+		{`[1, 2, 3, 4, 5][3:0:-1]`, []interface{}{4, 3, 2}},
+		{`[1, 2, 3, 4, 5][-100:100]`, []interface{}{1, 2, 3, 4, 5}},
+		{`[1, 2, 3, 4, 5][100:]`, []interface{}{}},
+		{`[1, 2, 3, 4, 5][3:1]`, []interface{}{}},
+		{`len([1, 2, 3][5:10])`, 0},
+		{`let xs = [1, 2, 3]; let ys = xs[:]; ys[0] = 10; xs[0]`, 1},
+		{`"hello"[1:4]`, "ell"},
+		{`"hello"[::-1]`, "olleh"},
+		{`"héllo"[1:3]`, "él"},
+		{`"héllo"[-4:-2]`, "él"},
+		{`"héllo"[1]`, "é"},
+		{`"héllo"[-4]`, "é"},
+		{`len("héllo")`, 5},
+		{`let s = "日本語"; s[0:len(s)] == s`, true},
+		{`let s = "日本語"; s[len(s) - 1]`, "語"},
+		{`list("日本")`, []interface{}{"日", "本"}},
+		{`"日本語"[3]`, "str index out of range"},
+		{`[1, 2, 3][::0]`, "slice step cannot be zero"},
+		{`[1, 2, 3]["a":]`, "slice indices must be int, got str"},
+		{`1[1:]`, "object is not sliceable: int"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_RangeExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`list(0..5)`, []interface{}{0, 1, 2, 3, 4}},
+		{`list(0..=5)`, []interface{}{0, 1, 2, 3, 4, 5}},
+		{`list(0..10 step 3)`, []interface{}{0, 3, 6, 9}},
+		{`list(0..=9 step 3)`, []interface{}{0, 3, 6, 9}},
+		{`list(5..0 step -2)`, []interface{}{5, 3, 1}},
+		{`list(5..=1 step -2)`, []interface{}{5, 3, 1}},
+		{`list(5..0)`, []interface{}{}},
+		{`let n = 3; list(0..n + 1)`, []interface{}{0, 1, 2, 3}},
+		{`len(0..10)`, 10},
+		{`len(0..=10 step 5)`, 3},
+		{`len(0..10 step 3)`, 4},
+		{`len(10..0)`, 0},
+		{`len(0..1000000000000)`, 1000000000000},
+		{`(0..10 step 2)[3]`, 6},
+		{`(0..10)[-1]`, 9},
+		{`(0..10)[10]`, "range index out of range"},
+		{`(0..10)[2:5]`, []interface{}{2, 3, 4}},
+		{`let total = 0; for (x in 1..=4) { total = total + x; } total`, 10},
+		{`0..10 step 0`, "range step cannot be zero"},
+		{`0.."a"`, "range bounds must be int, got str"},
+		{`len(-9000000000000000000..9000000000000000000)`, "range is too long"},
+		{`list(9223372036854775805..=9223372036854775807)`, []interface{}{9223372036854775805, 9223372036854775806, 9223372036854775807}},
+		{`9223372036854775807 in (0..=9223372036854775807 step 9223372036854775807)`, true},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_Comprehensions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[x * x for x in [1, 2, 3]]`, []interface{}{1, 4, 9}},
+		{`[x for x in [-1, 2, -3, 4] if x > 0]`, []interface{}{2, 4}},
+		{`[x for x in 0..10 if x > 2 if x < 6]`, []interface{}{3, 4, 5}},
+		{`[[x, y] for x in 1..3 for y in 0..x]`, []interface{}{[]interface{}{1, 0}, []interface{}{2, 0}, []interface{}{2, 1}}},
+		{`[a + b for [a, b] in [[1, 2], [3, 4]]]`, []interface{}{3, 7}},
+		{`[k for k, v in items({"a": 1, "b": 2}) if v > 1]`, []interface{}{"b"}},
+		{`[c for c in "abc"]`, []interface{}{"a", "b", "c"}},
+		{`[x for x in []]`, []interface{}{}},
+		{`let x = 10; [x for x in [1, 2]]; x`, 10},
+		{`let m = {k: v * 10 for k, v in items({"a": 1, "b": 2})}; [m["a"], m["b"]]`, []interface{}{10, 20}},
+		{`let m = {x: x * x for x in 1..=3 if x != 2}; list(m)`, []interface{}{1, 3}},
+		{`len({x: true for x in [1, 1, 2]})`, 2},
+		{`[x for x in 1]`, "object is not iterable: int"},
+		{`[y for x in [1]]`, "identifier not found: y"},
+		{`{[x]: x for x in [1]}`, "unhashable type: List"},
+		{`let f = fn(xs) { return [x? for x in xs]; }; is_error(f([1, error("e")]))`, true},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_Items(t *testing.T) {
+	obj := testParseInput(`items({"a": 1, "b": [2]})`)
+	testResult(t, obj, []interface{}{[]interface{}{"a", 1}, []interface{}{"b", []interface{}{2}}})
+
+	obj = testParseInput(`items([1])`)
+	testResult(t, obj, "expected positional argument 1 to be type Map but received type List")
+}
+
+func TestEval_NullSafety(t *testing.T) {
+	config := `let cfg = {"db": {"host": "db.local", "port": 5432}, "cache": null, "tags": ["a"]};`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`null`, nil},
+		{`null == null`, true},
+		{`let x = null; x`, nil},
+		{`fn() {}() == null`, true},
+		{`[1 == null, null == 1, "a" != null, null != "a", null != null]`, []interface{}{false, false, true, true, false}},
+		{`struct P { x }; [P(1) == null, null != P(1)]`, []interface{}{false, true}},
+		{`let f = fn(x) { if (x == null) { return "none"; }; return x; }; [f(null), f(2)]`, []interface{}{"none", 2}},
+		{`null ?? 1`, 1},
+		{`2 ?? 1`, 2},
+		{`false ?? 1`, false},
+		{`null ?? null ?? 3`, 3},
+		{`1 ?? missing`, 1},
+		{`null ?? missing`, "identifier not found: missing"},
+		{config + `cfg?.db?.host`, "db.local"},
+		{config + `cfg?.cache?.host`, nil},
+		{config + `cfg?.cache?.host ?? "localhost"`, "localhost"},
+		{config + `cfg?.missing`, "Map has no member missing"},
+		{config + `cfg?.db?.user ?? "root"`, "Map has no member user"},
+		{`{"a": 1}?.z`, "Map has no member z"},
+		{`{"a": 1}?.a`, 1},
+		{config + `cfg?["db"]?["port"]`, 5432},
+		{config + `cfg?["cache"]?["port"]`, nil},
+		{config + `cfg?.tags?[0]`, "a"},
+		{config + `cfg?.tags?[5] ?? "none"`, "list index out of range"},
+		{config + `cfg?["missing"]`, `key not found: "missing"`},
+		{`[1]?[5]`, "list index out of range"},
+		{`null?[0]`, nil},
+		{`1?.a`, "int has no member a"},
+		{`null?.upper()`, nil},
+		{`let s = null; s?.upper().lower()`, nil},
+		{`"a"?.upper()`, "A"},
+		{config + `cfg?.cache?.host.name`, nil},
+		{config + `cfg.cache?.servers[0].host`, nil},
+		{config + `cfg.cache?["servers"][0:1]`, nil},
+		{config + `cfg.cache?.get("host")`, nil},
+		{config + `cfg.cache.host`, "NULL has no member host"},
+		{`let calls = 0; let f = fn() { calls = calls + 1; }; null?.a(f()); calls`, 0},
+		{`let f = fn() { defer null?.close(); return 1; }; f()`, 1},
+		{`match (null) { null => "nothing", _ => "something" }`, "nothing"},
+		{`match (1) { null => "nothing", _ => "something" }`, "something"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_MembershipOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1 in [1, 2, 3]`, true},
+		{`4 in [1, 2, 3]`, false},
+		{`"1" in [1, 2, 3]`, false},
+		{`4 not in [1, 2, 3]`, true},
+		{`1 not in [1, 2, 3]`, false},
+		{`"k" in {"k": 1}`, true},
+		{`1 in {"k": 1}`, false},
+		{`"v" not in {"k": "v"}`, true},
+		{`"ell" in "hello"`, true},
+		{`"z" in "hello"`, false},
+		{`"" in "hello"`, true},
+		{`5 in 0..10`, true},
+		{`5 in 0..10 step 2`, false},
+		{`10 in 0..=10`, true},
+		{`1 + 1 in [2]`, true},
+		{`[x for x in 0..10 if x in [2, 4]]`, []interface{}{2, 4}},
+		{`1 in "123"`, "'in str' requires str as left operand, not int"},
+		{`1 in 1`, "argument of type int is not a container"},
+		{`null is null`, true},
+		{`let x = null; x is null`, true},
+		{`1 is null`, false},
+		{`1 is not null`, true},
+		{`let not = 5; let is = 2; not is not is`, true},
+		{`let not = [1]; 1 not in not`, false},
+		{`null is not null`, false},
+		{`true is true`, true},
+		{`let xs = [1]; let ys = xs; xs is ys`, true},
+		{`[1] is [1]`, false},
+		{`let f = fn(x) { if (x is null) { return "none"; } return x; }; [f(null), f(1)]`, []interface{}{"none", 1}},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_Structs(t *testing.T) {
+	point := `struct Point {
+    x, y
+    fn norm() { return self.x * self.x + self.y * self.y; }
+    fn add(other) { return Point(self.x + other.x, self.y + other.y); }
+    fn move(dx) { self.x = self.x + dx; }
+};
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{point + `let p = Point(1, 2); p.x`, 1},
+		{point + `let p = Point(1, 2); p.y`, 2},
+		{point + `Point(3, 4).norm()`, 25},
+		{point + `Point(1, 2).add(Point(3, 4)).y`, 6},
+		{point + `let p = Point(1, 2); p.move(2); p.x`, 3},
+		{point + `let p = Point(1, 2); p.x = 5; p.x`, 5},
+		{point + `let f = Point(1, 2).norm; f()`, 5},
+		{point + `Point(1, 2) == Point(1, 2)`, true},
+		{point + `Point(1, 2) == Point(2, 1)`, false},
+		{point + `Point(1, 2) != Point(1, 2)`, false},
+		{point + `Point(Point(0, 1), 2) == Point(Point(0, 1), 2)`, true},
+		{point + `Point([1], 2) == Point([1], 2)`, true},
+		{point + `Point([1], 2) == Point([1, 2], 2)`, false},
+		{point + `Point([[1], "a"], 2) == Point([[1], "a"], 2)`, true},
+		{point + `Point({"a": [1], "b": 2}, 0) == Point({"b": 2, "a": [1]}, 0)`, true},
+		{point + `Point({"a": [1]}, 0) == Point({"a": [2]}, 0)`, false},
+		{point + `Point({"a": 1}, 0) == Point({"a": 1, "b": 2}, 0)`, false},
+		{point + `[1] in [[0], [1]]`, true},
+		{point + `struct Other { x, y }; Point(1, 2) == Other(1, 2)`, "type mismatch: Point == Other"},
+		{point + `Point(1, 2) < Point(1, 2)`, "Point does not support < operator"},
+		{point + `Point(1, 2) in [Point(0, 0), Point(1, 2)]`, true},
+		{point + `Point(1, 2).z`, "Point has no member z"},
+		{point + `Point(1, 2)?.z`, "Point has no member z"},
+		{point + `let p = Point(1, 2); p.z = 1`, "Point has no field z"},
+		{point + `Point(1)`, "Point expected 2 positional arguments but received 1"},
+		{point + `let p = freeze(Point(1, 2)); p.x = 1`, "cannot modify frozen Point"},
+		{point + `match (Point(1, 2)) { p => p.y }`, 2},
+		{`let m = {"a": 1}; m.a = 2; m.a`, 2},
+		{`let m = {"a": 1}; m.b`, "Map has no member b"},
+		{`let m = freeze({"a": 1}); m.a = 2`, "cannot modify frozen Map"},
+		{`struct Empty {}; Empty() == Empty()`, true},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_StructInspect(t *testing.T) {
+	obj := testParseInput(`struct Point { x, y }; [Point, Point(1, "a")]`)
+	require.Equal(t, `[struct Point, Point{x: 1, y: "a"}]`, obj.Inspect())
+}
+
+func TestEval_Enums(t *testing.T) {
+	decls := `enum Color { Red, Green, Blue };
+enum Shape { Circle(r), Rect(w, h), Empty };
+let area = fn(s) {
+    return match (s) {
+        Shape.Circle(r) => 3 * r * r,
+        Shape.Rect(w, h) => w * h,
+        Shape.Empty => 0
+    };
+};
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{decls + `Color.Red == Color.Red`, true},
+		{decls + `Color.Red == Color.Green`, false},
+		{decls + `Color.Red is Color.Red`, true},
+		{decls + `Shape.Circle(1) == Shape.Circle(1)`, true},
+		{decls + `Shape.Circle(1) == Shape.Circle(2)`, false},
+		{decls + `Shape.Circle(1) == Shape.Empty`, false},
+		{decls + `Color.Red == Shape.Empty`, "type mismatch: Color == Shape"},
+		{decls + `Shape.Rect(2, 3).h`, 3},
+		{decls + `[area(Shape.Circle(2)), area(Shape.Rect(2, 3)), area(Shape.Empty)]`, []interface{}{12, 6, 0}},
+		{decls + `match (Color.Green) { Color.Red => "stop", Color.Green => "go", _ => "wait" }`, "go"},
+		{decls + `match (Shape.Rect(1, 2)) { Shape.Rect => "rect", _ => "other" }`, "rect"},
+		{decls + `match (Shape.Circle(5)) { Shape.Circle(r) if r > 3 => "big", Shape.Circle(_) => "small" }`, "big"},
+		{decls + `match (1) { Color.Red => "red", _ => "other" }`, "other"},
+		{decls + `let m = {Color.Red: "stop", Color.Green: "go"}; m[Color.Green]`, "go"},
+		{decls + `let m = {Shape.Circle(1): "one"}; m[Shape.Circle(1)]`, "one"},
+		{decls + `let m = {Shape.Circle([1]): "one"}`, "unhashable type: Shape"},
+		{decls + `Color.Red in [Color.Green, Color.Red]`, true},
+		{decls + `Color.Purple`, "Color has no member Purple"},
+		{decls + `Color.Purple ?? Color.Red`, "Color has no member Purple"},
+		{decls + `Color?.Purple ?? Color.Red`, "Color has no member Purple"},
+		{decls + `Shape.Circle(1, 2)`, "Shape.Circle expected 1 positional arguments but received 2"},
+		{decls + `Color.Red()`, "not a function Color"},
+		{decls + `match (Shape.Empty) { Color.Red => 1 }`, "no pattern matched value Shape.Empty"},
+		{decls + `match (Shape.Circle(1)) { Shape.Circle(a, b) => 1, Shape.Circle(a) => a }`, 1},
+		{decls + `match (Color.Red) { Shape.Empty => 1 }`, "no pattern matched value Color.Red"},
+		{`enum E { A, A }`, "duplicate variant A in enum E"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_EnumInspect(t *testing.T) {
+	obj := testParseInput(`enum Shape { Circle(r), Rect(w, h), Empty };
+[Shape, Shape.Circle, Shape.Circle(1), Shape.Rect("a", [2]), Shape.Empty]`)
+	require.Equal(
+		t,
+		`[enum Shape, Shape.Circle(r), Shape.Circle(1), Shape.Rect("a", [2]), Shape.Empty]`,
+		obj.Inspect(),
+	)
+}
+
+func TestEval_StructProtocols(t *testing.T) {
+	vec := `struct Vec {
+    x, y
+    fn add(other) { return Vec(self.x + other.x, self.y + other.y); }
+    fn eq(other) { return self.x == other.x; }
+    fn lt(other) { return self.x < other.x; }
+    fn len() { return 2; }
+    fn iter() { return [self.x, self.y]; }
+    fn str() { return "<" + str(self.x) + ", " + str(self.y) + ">"; }
+    fn index(i) { return [self.x, self.y][i]; }
+};
+struct Bad {
+    v
+    fn eq(other) { return 1; }
+    fn len() { return "a"; }
+    fn iter() { return 1; }
+    fn str() { return 1; }
+};
+struct Plain { v };
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{vec + `(Vec(1, 2) + Vec(3, 4)).y`, 6},
+		{vec + `add(Vec(1, 2), Vec(3, 4)).x`, 4},
+		{vec + `Vec(1, 2) == Vec(1, 5)`, true},
+		{vec + `Vec(1, 2) != Vec(2, 2)`, true},
+		{vec + `Vec(1, 2) < Vec(2, 0)`, true},
+		{vec + `Vec(3, 2) > Vec(2, 0)`, true},
+		{vec + `len(Vec(0, 0))`, 2},
+		{vec + `let total = 0; for (v in Vec(3, 4)) { total = total + v; }; total`, 7},
+		{vec + `[v * 2 for v in Vec(3, 4)]`, []interface{}{6, 8}},
+		{vec + `str(Vec(1, 2))`, "<1, 2>"},
+		{vec + `Vec(1, 2)[1]`, 2},
+		{vec + `Vec(1, 2) in [Vec(1, 0)]`, true},
+		{vec + `Vec(1, 2) + 1`, "type mismatch: Vec + int"},
+		{vec + `Bad(1) == Bad(1)`, "eq must return bool, got int"},
+		{vec + `len(Bad(1))`, "len must return int, got str"},
+		{vec + `for (v in Bad(1)) {}`, "object is not iterable: int"},
+		{vec + `str(Bad(1))`, "str must return str, got int"},
+		{vec + `Plain(1) + Plain(2)`, "invalid operation: Plain + Plain"},
+		{vec + `Plain(1) < Plain(2)`, "Plain does not support < operator"},
+		{vec + `Plain(1) > Plain(2)`, "Plain does not support < operator"},
+		{vec + `Plain(2) > Plain(1)`, "Plain does not support < operator"},
+		{vec + `Vec(1, 2) > Vec(1, 5)`, false},
+		{vec + `Vec(1, 2) > Vec(3, 0)`, false},
+		{vec + `len(Plain(1))`, "object is not iterable: Plain"},
+		{vec + `for (v in Plain(1)) {}`, "object is not iterable: Plain"},
+		{vec + `Plain(1)[0]`, "Plain does not support indexing"},
+		{vec + `str(Plain(1))`, "Plain{v: 1}"},
+		{`str(1) + str("a") + str([1, "b"])`, `1a[1, "b"]`},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_BuiltinMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"abc".upper()`, "ABC"},
+		{`"ABC".lower()`, "abc"},
+		{`"  hi  ".trim()`, "hi"},
+		{`"a,b,c".split(",")`, []interface{}{"a", "b", "c"}},
+		{`" a  b ".split()`, []interface{}{"a", "b"}},
+		{`"a-b-c".replace("-", "+")`, "a+b+c"},
+		{`"hello".starts_with("he")`, true},
+		{`"hello".starts_with("lo")`, false},
+		{`"hello".ends_with("lo")`, true},
+		{`let up = "abc".upper; up()`, "ABC"},
+		{`"abc".replace("a")`, "replace expected 2 positional arguments but received 1"},
+		{`"abc".split(1)`, "split expected positional argument 1 to be type str but received type int"},
+		{`"abc".trim(1)`, "trim expected 0 positional arguments but received 1"},
+		{`"abc".nope()`, "str has no member nope"},
+		{`let xs = [1]; xs.push(2, 3); xs`, []interface{}{1, 2, 3}},
+		{`let xs = [1, 2, 3]; [xs.pop(), xs]`, []interface{}{3, []interface{}{1, 2}}},
+		{`let xs = [1, 2, 3]; [xs.pop(0), xs]`, []interface{}{1, []interface{}{2, 3}}},
+		{`[].pop()`, "pop from empty list"},
+		{`[1].pop(5)`, "pop index out of range"},
+		{`[1, 2].pop(1, 2)`, "pop expected 0 to 1 positional arguments but received 2"},
+		{`["a", "b"].index("b")`, 1},
+		{`["a", "b"].index("c")`, `"c" is not in list`},
+		{`let xs = [3, 1, 2]; xs.sort(); xs`, []interface{}{1, 2, 3}},
+		{`let xs = ["b", "a"]; xs.sort(); xs`, []interface{}{"a", "b"}},
+		{`[1, "a"].sort()`, "type mismatch: str < int"},
+		{`["b", "a"] == ["b", "a"]`, "invalid operation: List == List"},
+		{`"a" == "a"`, true},
+		{`"a" < "b"`, true},
+		{`"a" in ["b", "a"]`, true},
+		{`let xs = [1, 2, 3]; xs.reverse(); xs`, []interface{}{3, 2, 1}},
+		{`freeze([1]).push(2)`, "cannot modify frozen List"},
+		{`freeze([2, 1]).sort()`, "cannot modify frozen List"},
+		{`[1].nope`, "List has no member nope"},
+		{`{"a": 1}.get("a")`, 1},
+		{`{"a": 1}.get("b")`, nil},
+		{`{"a": 1}.get("b", 2)`, 2},
+		{`{"a": 1, "b": 2}.keys()`, []interface{}{"a", "b"}},
+		{`{"a": 1, "b": 2}.values()`, []interface{}{1, 2}},
+		{`{"a": 1}.items()`, []interface{}{[]interface{}{"a", 1}}},
+		{`{"get": 1}.get`, 1},
+		{`{"a": 1}.nope`, "Map has no member nope"},
+		{`1.upper()`, "int has no member upper"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_Generators(t *testing.T) {
+	count := `let count = fn(n) { let i = 0; for (x in 0..n) { yield i; i = i + 1; } };
+let naturals = fn() { let i = 0; for (x in 0..1000000000) { yield i; i = i + 1; } };
+`
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{count + `list(count(3))`, []interface{}{0, 1, 2}},
+		{count + `let g = count(2); [next(g), next(g), next(g, "done")]`, []interface{}{0, 1, "done"}},
+		{count + `let g = count(0); next(g)`, "generator is exhausted"},
+		{count + `let total = 0; for (x in count(4)) { total = total + x; }; total`, 6},
+		{count + `[x * x for x in count(4) if x > 1]`, []interface{}{4, 9}},
+		{count + `let g = naturals(); [next(g), next(g), next(g)]`, []interface{}{0, 1, 2}},
+		{count + `let g = count(2); list(g); list(g)`, []interface{}{}},
+		{count + `let g = count(1); g is g`, true},
+		{`let f = fn() { yield 1; return 5; yield 2; }; list(f())`, []interface{}{1}},
+		{`let f = fn() { yield 1; throw "boom"; }; list(f())`, "boom"},
+		{`let f = fn() { yield 1; throw "boom"; }; let g = f(); [next(g), is_error(next(g))]`, "boom"},
+		{`let f = fn() { yield 1; throw "boom"; }; for (x in f()) {}`, "boom"},
+		{`let f = fn() { yield [1, 2]; yield [3, 4]; }; [a + b for [a, b] in f()]`, []interface{}{3, 7}},
+		{`let f = fn(xs) { for (x in xs) { if (x > 1) { yield x; } } }; list(f([1, 2, 3]))`, []interface{}{2, 3}},
+		{`let f = fn() { let g = fn() { return 1; }; yield g(); }; list(f())`, []interface{}{1}},
+		{`let log = []; let f = fn() { defer log.push("closed"); yield 1; }; list(f()); log`, []interface{}{"closed"}},
+		{`let f = fn() { yield 1; }; f()`, "<generator>"},
+		{`struct R { n
+    fn iter() { for (x in 0..self.n) { yield x; } }
+}; list(R(2).iter())`, []interface{}{0, 1}},
+		{`next([1])`, "object is not an iterator: List"},
+		{`let g = null; let f = fn() { yield next(g); }; g = f(); next(g)`, "generator already executing"},
+		{`let g = null; let f = fn() { let m = ""; try { next(g); } catch (ValueError e) { m = e.message; }; yield m; }; g = f(); next(g)`, "generator already executing"},
+		{`let g = null; let f = fn() { for (x in g) {} yield 1; }; g = f(); list(g)`, "generator already executing"},
+		{`len(fn() { yield 1; }())`, "object is not iterable: Generator"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			if s, ok := subtest.expected.(string); ok && s == "<generator>" {
+				require.Equal(t, s, obj.Inspect())
+				return
+			}
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_GeneratorClose(t *testing.T) {
+	obj := testParseInput(`let log = [];
+let f = fn() { defer log.push("deferred"); yield 1; yield 2; };
+let g = f();
+next(g);
+[g, log]`)
+	list := obj.(*object.List)
+	g := list.Values()[0].(*object.Generator)
+	log := list.Values()[1].(*object.List)
+	require.Empty(t, log.Values())
+
+	g.Close()
+	require.Len(t, log.Values(), 1)
+	_, ok := g.Next()
+	require.False(t, ok)
+}
+
+func TestEval_Concurrency(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let f = fn(x) { return x * 2; }; await spawn f(21)`, 42},
+		{`let f = fn(a, b) { return a - b; }; await spawn f(5, b = 2)`, 3},
+		{`let f = fn(a) { return a; }; await spawn f(1, b = 2)`, "unexpected keyword argument b"},
+		{`let f = fn(x) { return x * 2; }; let ts = [spawn f(x) for x in 0..4]; [await t for t in ts]`, []interface{}{0, 2, 4, 6}},
+		{`let f = fn() { throw "boom"; }; let t = spawn f(); await t`, "boom"},
+		{`let f = fn() { throw "boom"; }; let t = spawn f(); let m = ""; try { await t; } catch (e) { m = e.message; }; m`, "boom"},
+		{`await 1`, "cannot await int"},
+		{`let ch = channel(1); ch.send(1); ch.recv()`, 1},
+		{`let ch = channel(); let f = fn() { ch.send("hi"); }; spawn f(); ch.recv()`, "hi"},
+		{`let ch = channel(3); ch.send(1); ch.send(2); ch.close(); [ch.recv(), ch.recv(), ch.recv()]`, []interface{}{1, 2, nil}},
+		{`let ch = channel(); let f = fn() { for (x in 0..3) { ch.send(x); }; ch.close(); }; spawn f(); list(ch)`, []interface{}{0, 1, 2}},
+		{`let ch = channel(); ch.close(); ch.send(1)`, "send on closed channel"},
+		{`let ch = channel(); ch.close(); ch.close()`, "close of closed channel"},
+		{`channel(-1)`, "channel size must not be negative"},
+		{`channel(9223372036854775807)`, "channel size must not be more than 1048576"},
+		{`let ch = channel(1048576); ch.send(1); ch.recv()`, 1},
+		{`let a = channel(1); let b = channel(1); b.send(2); select { v = recv(a) => ["a", v], v = recv(b) => ["b", v] }`, []interface{}{"b", 2}},
+		{`let a = channel(); select { v = recv(a) => v, default => "empty" }`, "empty"},
+		{`let a = channel(1); select { send(a, 5) => a.recv() }`, 5},
+		{`let a = channel(); a.close(); select { v = recv(a) => v }`, nil},
+		{`let a = channel(); a.close(); select { send(a, 1) => 1 }`, "send on closed channel"},
+		{`select { recv(1) => 1 }`, "cannot recv on int"},
+		{`let results = channel(10);
+let work = fn(n) { results.send(n * n); };
+let tasks = [spawn work(n) for n in 1..=4];
+for (t in tasks) { await t; };
+results.close();
+let total = 0;
+for (r in results) { total = total + r; };
+total`, 30},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+// TestEval_SharedCollections writes to one map and one list from several
+// tasks. Run with -race to check the collections lock their contents.
+func TestEval_SharedCollections(t *testing.T) {
+	obj := testParseInput(`import "strings";
+let m = {};
+let l = [];
+let xs = [0];
+let f = fn(n) {
+    for (i in 0..100) {
+        m[str(n) + "-" + str(i)] = i;
+        m.get("0-0");
+        l.push(i);
+        if (i < 10) { l.pop(); len(l); l.sort(); }
+        xs.push(i);
+        let [a, ...r] = xs;
+        strings.join([str(x) for x in r], ",");
+    }
+};
+let tasks = [spawn f(n) for n in 0..8];
+for (t in tasks) { await t; };
+[len(m), len(l)]`)
+	testResult(t, obj, []interface{}{800, 720})
+}
+
+// TestEval_SharedInstance writes to the fields of one struct instance from
+// several tasks. Run with -race to check instances lock their fields.
+func TestEval_SharedInstance(t *testing.T) {
+	obj := testParseInput(`struct Counter { n, last }
+let c = Counter(0, null);
+let f = fn(n) {
+    for (i in 0..100) {
+        c.last = n;
+        c.n;
+        str(c);
+        c == Counter(0, null);
+    }
+};
+let tasks = [spawn f(n) for n in 0..8];
+for (t in tasks) { await t; };
+c.last in 0..8`)
+	testResult(t, obj, true)
+}
+
+func TestEval_KeywordArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let f = fn(a, b) { return a - b; }; f(b = 1, a = 5)`, 4},
+		{`let f = fn(a, b) { return a - b; }; f(5, b = 1)`, 4},
+		{`let f = fn(a, b) { return a - b; }; f(5, c = 1)`, "unexpected keyword argument c"},
+		{`let f = fn(a, b) { return a - b; }; f(5, a = 1)`, "multiple values for argument a"},
+		{`let f = fn(a, b) { return a - b; }; f(5)`, "missing argument b"},
+		{`let f = fn(a) { return a; }; f(a = 1, a = 2)`, "keyword argument a repeated"},
+		{`len([1], n = 1)`, "unexpected keyword argument n"},
+		{`struct P { x }; P(x = 1)`, "unexpected keyword argument x"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_TaskGroups(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`parallel_map(fn(x) { return x * x; }, [1, 2, 3, 4])`, []interface{}{1, 4, 9, 16}},
+		{`parallel_map(fn(x) { return x * 2; }, 0..5, workers = 2)`, []interface{}{0, 2, 4, 6, 8}},
+		{`parallel_map(fn(x) { return x; }, [], workers = 1)`, []interface{}{}},
+		{`parallel_map(fn(x) { if (x == 2) { throw "bad " + str(x); }; return x; }, [1, 2, 3])`, "bad 2"},
+		{`parallel_map(fn(x) { return x; }, [1], workers = 0)`, "parallel_map expected at least 1 worker"},
+		{`parallel_map(fn(x) { return x; }, [1], size = 1)`, "unexpected keyword argument size"},
+		{`parallel_map(fn(x) { return x; }, 1)`, "object int is not iterable"},
+		{`let ch = channel(3);
+let f = fn(x) { time.sleep(10); ch.send(x); };
+group { spawn f(1); spawn f(2); spawn f(3); };
+ch.close();
+len(list(ch))`, 3},
+		{`let log = [];
+let slow = fn() { time.sleep(5000); log.push("finished"); };
+let fail = fn() { time.sleep(10); throw "boom"; };
+let m = "";
+try { group { spawn slow(); spawn fail(); }; } catch (e) { m = e.message; };
+[m, log]`, []interface{}{"boom", []interface{}{}}},
+		{`let ch = channel();
+let wait = fn() { return ch.recv(); };
+let fail = fn() { throw "boom"; };
+group { spawn wait(); spawn fail(); }`, "boom"},
+		{`let f = fn() { time.sleep(5000); };
+group { spawn f(); throw "first"; }`, "first"},
+		{`let inner = fn(x) { return x; };
+let outer = fn() { spawn inner(1); return 1; };
+group { spawn outer(); }`, nil},
+		{`let f = fn() { group { return 5; }; return 1; }; f()`, 5},
+		{`let f = fn() { for (x in 0..1000000000) {} };
+let fail = fn() { throw "stop"; };
+group { spawn f(); spawn fail(); }`, "stop"},
+		{`let ch = channel();
+let drain = fn() { for (x in ch) {} };
+let fail = fn() { throw "stop"; };
+group { spawn drain(); spawn fail(); }`, "stop"},
+		{`let ch = channel();
+let collect = fn() { return list(ch); };
+let fail = fn() { throw "stop"; };
+group { spawn collect(); spawn fail(); }`, "stop"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_EvalContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p := parser.New(lexer.New(`let f = fn() { return 1; }; f()`))
+	obj := EvalContext(ctx, p.ParseProgram(), object.NewEnv())
+	err, ok := obj.(*object.Error)
+	require.True(t, ok)
+	require.Equal(t, object.ErrorType(object.ErrorTypeCancelled), err.ErrorType)
+}
+
+func TestEval_ChannelIterCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	p := parser.New(lexer.New(`let ch = channel(); for (x in ch) {}`))
+	obj := EvalContext(ctx, p.ParseProgram(), object.NewEnv())
+	err, ok := obj.(*object.Error)
+	require.True(t, ok)
+	require.Equal(t, object.ErrorType(object.ErrorTypeCancelled), err.ErrorType)
+}
+
+// TestEval_ProtocolCancelled checks that struct methods called by
+// operators and builtins run with the ctx of the caller
+func TestEval_ProtocolCancelled(t *testing.T) {
+	slow := `import "math";
+let spin = fn() { for (x in 0..1000000000) {}; };
+struct Slow {
+    fn add(o) { return spin(); }
+    fn eq(o) { return spin(); }
+    fn lt(o) { return spin(); }
+    fn len() { return spin(); }
+    fn str() { return spin(); }
+    fn index(k) { return spin(); }
+    fn iter() { return spin(); }
+};
+let s = Slow();
+`
+	tests := []string{
+		`s + s`,
+		`s == s`,
+		`s < s`,
+		`s > s`,
+		`[s, s].sort()`,
+		`math.min(s, s)`,
+		`len(s)`,
+		`str(s)`,
+		`"${s}"`,
+		`s[0]`,
+		`for (x in s) {}`,
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			p := parser.New(lexer.New(slow + input))
+			obj := EvalContext(ctx, p.ParseProgram(), object.NewEnv())
+			err, ok := obj.(*object.Error)
+			require.True(t, ok, obj.Inspect())
+			require.Equal(t, object.ErrorType(object.ErrorTypeCancelled), err.ErrorType)
+		})
+	}
+}
+
+func TestEval_InterpolatedString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let name = "mitch"; let count = 2; "hello ${name}, you have ${count + 1} items"`, "hello mitch, you have 3 items"},
+		{`"n=${1}"`, "n=1"},
+		{`"${[1, "a"]} ${{"k": true}} ${null}"`, `[1, "a"] {"k": true} null`},
+		{`let xs = [1, 2]; "${len(xs)}${xs[0]}"`, "21"},
+		{`"${"inner ${1 + 1}"}!"`, "inner 2!"},
+		{`struct P { x
+    fn str() { return "P(${self.x})"; }
+}; "point ${P(3)}"`, "point P(3)"},
+		{`struct Bad { fn str() { return 1; } }; "${Bad()}"`, "str must return str, got int"},
+		{`"${nope}"`, "identifier not found: nope"},
+		{`"${[1][3]}"`, "index out of range"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_Help(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`/// Adds a and b.
+/// Both must be ints.
+let add = fn(a, b) { return a + b; };
+help(add)`, "Adds a and b.\nBoth must be ints."},
+		{`let f = fn() { return 1; }; help(f)`, nil},
+		{`let apply = fn(f) { return help(f); }; apply(/// Inline.
+fn() { return 1; })`, "Inline."},
+		{`struct P { x
+    /// Returns x.
+    fn get() { return self.x; }
+}; help(P(1).get)`, "Returns x."},
+		{`help(len)`, nil},
+		{`help(1)`, nil},
+		{`help()`, "expected 1 positional argument but received 0"},
+		{`/* a /* nested */ comment */ 1 + /* inline */ 2`, 3},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
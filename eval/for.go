@@ -0,0 +1,44 @@
+package eval
+
+import (
+	"mitchlang/ast"
+	"mitchlang/object"
+)
+
+func evalForStatement(n *ast.ForStatement, env *object.Env) object.Object {
+	iterable := Eval(n.Iterable, env)
+	if isUnwinding(iterable) {
+		return iterable
+	}
+	it, err := object.Iter(env.Context(), iterable)
+	if err != nil {
+		return err
+	}
+	for {
+		value, ok := it.Next()
+		if !ok {
+			break
+		}
+		if err := env.Context().Err(); err != nil {
+			return object.NewCancelledError(err)
+		}
+		// generators produce the error that stopped them as their last
+		// value
+		if err, ok := value.(*object.Error); ok {
+			return err
+		}
+		// loop variables are bound in the enclosing scope, the same
+		// as any other binding made in the body
+		if err := destructure(n.Pattern, value, env); err != nil {
+			return err
+		}
+		out := Eval(n.Body, env)
+		switch out.(type) {
+		case *object.Error:
+			return out
+		case *object.ReturnValue:
+			return out
+		}
+	}
+	return object.NullValue
+}
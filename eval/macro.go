@@ -0,0 +1,275 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/object"
+	"sync/atomic"
+)
+
+// maxExpansionDepth limits how many times the code returned by a macro is
+// expanded again, which stops macros that expand to themselves
+const maxExpansionDepth = 100
+
+// DefineMacros binds each macro defined by a let statement at the top
+// level of program in env and removes the definitions from program
+func DefineMacros(program *ast.Program, env *object.Env) {
+	statements := make([]ast.Statement, 0, len(program.Statements))
+	for _, statement := range program.Statements {
+		let, ok := statement.(*ast.LetStatement)
+		if !ok || let.Name == nil {
+			statements = append(statements, statement)
+			continue
+		}
+		macro, ok := let.Value.(*ast.MacroLiteral)
+		if !ok {
+			statements = append(statements, statement)
+			continue
+		}
+		env.Set(let.Name.Value, &object.Macro{
+			Name:       let.Name.Value,
+			Parameters: macro.Parameters,
+			Body:       macro.Body,
+			Env:        env,
+			Doc:        let.Doc,
+		})
+	}
+	program.Statements = statements
+}
+
+// ExpandMacros replaces each call of a macro bound in env with the code
+// the macro returns. The returned code is expanded in turn.
+func ExpandMacros(program ast.Node, env *object.Env) (ast.Node, *object.Error) {
+	return expandMacros(program, env, 0)
+}
+
+func expandMacros(node ast.Node, env *object.Env, depth int) (ast.Node, *object.Error) {
+	var err *object.Error
+	node = ast.Modify(node, func(node ast.Node) ast.Node {
+		call, ok := node.(*ast.CallExpression)
+		if !ok || err != nil {
+			return node
+		}
+		macro := lookupMacro(call, env)
+		if macro == nil {
+			return node
+		}
+		if depth >= maxExpansionDepth {
+			err = &object.Error{Message: fmt.Sprintf("expansion of macro %s is too deep", macro.Name)}
+			return node
+		}
+		var out ast.Node
+		out, err = expandMacro(macro, call)
+		if err != nil {
+			return node
+		}
+		out, err = expandMacros(out, env, depth+1)
+		return out
+	})
+	return node, err
+}
+
+func lookupMacro(call *ast.CallExpression, env *object.Env) *object.Macro {
+	ident, ok := call.Function.(*ast.Identifier)
+	if !ok {
+		return nil
+	}
+	obj, ok := env.Get(ident.Value)
+	if !ok {
+		return nil
+	}
+	macro, _ := obj.(*object.Macro)
+	return macro
+}
+
+// expandMacro calls macro with the code of each argument of call and
+// returns the code the macro returns
+func expandMacro(macro *object.Macro, call *ast.CallExpression) (ast.Node, *object.Error) {
+	if len(call.Keywords) > 0 {
+		return nil, object.NewTypeError("unexpected keyword argument %s", call.Keywords[0].Name)
+	}
+	if len(call.Arguments) != len(macro.Parameters) {
+		return nil, object.NewTypeError(
+			"macro %s expected %d arguments but received %d",
+			macro.Name, len(macro.Parameters), len(call.Arguments),
+		)
+	}
+	args := make([]object.Object, 0, len(call.Arguments))
+	for _, arg := range call.Arguments {
+		args = append(args, &object.Quote{Node: arg})
+	}
+	fn := &object.Function{Parameters: macro.Parameters, Body: macro.Body, Env: macro.Env}
+	ctx := context.WithValue(context.Background(), expansionKey{}, &expansion{names: map[string]string{}})
+
+	out := applyFunction(ctx, fn, args, nil)
+	if err, ok := out.(*object.Error); ok {
+		err.Trace = append(err.Trace, call.String())
+		return nil, err
+	}
+	quote, ok := out.(*object.Quote)
+	if !ok {
+		return nil, object.NewTypeError("macro %s must return a quote, not %s", macro.Name, out.Type())
+	}
+	return quote.Node, nil
+}
+
+// expansionKey is the context key of the expansion being run
+type expansionKey struct{}
+
+// expansion holds the fresh names given to the names bound by the code a
+// macro returns. Renaming them keeps the code from capturing or shadowing
+// the names used at the call site.
+type expansion struct {
+	names map[string]string
+}
+
+func currentExpansion(env *object.Env) *expansion {
+	exp, _ := env.Context().Value(expansionKey{}).(*expansion)
+	return exp
+}
+
+// gensyms counts the names made by gensym
+var gensyms int64
+
+// gensym returns a fresh name based on name. The names contain a # so
+// they can't clash with names written in the source.
+func gensym(name string) string {
+	return fmt.Sprintf("%s#%d", name, atomic.AddInt64(&gensyms, 1))
+}
+
+// rename gives each name bound in node a fresh name, leaving the
+// identifiers in skip alone. Keyword arguments naming a renamed parameter
+// are renamed too.
+func (e *expansion) rename(node ast.Node, skip map[*ast.Identifier]*ast.CallExpression) ast.Node {
+	ast.Inspect(node, func(node ast.Node) bool {
+		for _, name := range boundNames(node) {
+			if _, ok := e.names[name]; !ok {
+				e.names[name] = gensym(name)
+			}
+		}
+		return true
+	})
+	node = ast.Modify(node, func(node ast.Node) ast.Node {
+		ident, ok := node.(*ast.Identifier)
+		if !ok {
+			return node
+		}
+		if _, ok := skip[ident]; ok {
+			return node
+		}
+		if name, ok := e.names[ident.Value]; ok {
+			return &ast.Identifier{Token: ident.Token, Value: name}
+		}
+		return node
+	})
+	// Modify doesn't visit the names of keyword arguments, which are
+	// renamed when they name a renamed parameter of a function written
+	// in the quote
+	literals := map[string]*ast.FunctionLiteralExpression{}
+	ast.Inspect(node, func(node ast.Node) bool {
+		if let, ok := node.(*ast.LetStatement); ok && let.Name != nil {
+			if fn, ok := let.Value.(*ast.FunctionLiteralExpression); ok {
+				literals[let.Name.Value] = fn
+			}
+		}
+		return true
+	})
+	ast.Inspect(node, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpression)
+		if !ok {
+			return true
+		}
+		var fn *ast.FunctionLiteralExpression
+		switch callee := call.Function.(type) {
+		case *ast.FunctionLiteralExpression:
+			fn = callee
+		case *ast.Identifier:
+			fn = literals[callee.Value]
+		}
+		if fn == nil {
+			return true
+		}
+		for _, kw := range call.Keywords {
+			name, ok := e.names[kw.Name.Value]
+			if ok && hasParameter(fn, name) {
+				kw.Name = &ast.Identifier{Token: kw.Name.Token, Value: name}
+			}
+		}
+		return true
+	})
+	return node
+}
+
+// hasParameter reports whether fn has a parameter called name
+func hasParameter(fn *ast.FunctionLiteralExpression, name string) bool {
+	for _, param := range fn.Parameters {
+		if param.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// boundNames returns the names node binds
+func boundNames(node ast.Node) []string {
+	switch n := node.(type) {
+	case *ast.LetStatement:
+		if n.Pattern != nil {
+			return patternNames(n.Pattern)
+		}
+		return []string{n.Name.Value}
+	case *ast.FunctionLiteralExpression:
+		names := []string{}
+		for k, param := range n.Parameters {
+			if k < len(n.Patterns) && n.Patterns[k] != nil {
+				names = append(names, patternNames(n.Patterns[k])...)
+				continue
+			}
+			names = append(names, param.Value)
+		}
+		return names
+	case *ast.ForStatement:
+		return patternNames(n.Pattern)
+	case *ast.ListComprehension:
+		return clauseNames(n.Clauses)
+	case *ast.MapComprehension:
+		return clauseNames(n.Clauses)
+	case *ast.MatchExpression:
+		names := []string{}
+		for _, arm := range n.Arms {
+			names = append(names, patternNames(arm.Pattern)...)
+		}
+		return names
+	case *ast.TryStatement:
+		names := []string{}
+		for _, clause := range n.Catches {
+			if clause.Name != nil {
+				names = append(names, clause.Name.Value)
+			}
+		}
+		return names
+	case *ast.SelectExpression:
+		names := []string{}
+		for _, c := range n.Cases {
+			if c.Name != nil {
+				names = append(names, c.Name.Value)
+			}
+		}
+		return names
+	case *ast.StructStatement:
+		return []string{n.Name.Value}
+	case *ast.EnumStatement:
+		return []string{n.Name.Value}
+	default:
+		return nil
+	}
+}
+
+func clauseNames(clauses []*ast.ComprehensionClause) []string {
+	names := []string{}
+	for _, clause := range clauses {
+		names = append(names, patternNames(clause.Pattern)...)
+	}
+	return names
+}
@@ -0,0 +1,190 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"mitchlang/ast"
+	"mitchlang/lexer"
+	"mitchlang/object"
+	"mitchlang/parser"
+)
+
+// testExpand parses in and expands the macros it defines
+func testExpand(t *testing.T, in string) (ast.Node, *object.Env, *object.Error) {
+	p := parser.New(lexer.New(in))
+	program := p.ParseProgram()
+	require.Empty(t, p.Errors())
+	env := object.NewEnv()
+	DefineMacros(program, env)
+	expanded, err := ExpandMacros(program, env)
+	return expanded, env, err
+}
+
+func TestEval_Quote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(5)`, `quote(5)`},
+		{`quote(5 + 8)`, `quote((5 + 8))`},
+		{`quote(foo(bar))`, `quote(foo(bar))`},
+		{`quote(unquote(4 + 4))`, `quote(8)`},
+		{`quote(8 + unquote(4 + 4))`, `quote((8 + 8))`},
+		{`let x = 8; quote(x + unquote(x))`, `quote((x + 8))`},
+		{`let q = quote(4 + 4); quote(unquote(q) * 2)`, `quote(((4 + 4) * 2))`},
+		{`quote(unquote(true == false))`, `quote(false)`},
+		{`quote(unquote("a"))`, `quote("a")`},
+		{`quote(unquote([1, null]))`, `quote([1, null])`},
+		{`let f = fn() { return quote(x); }; [f(), f()]`, `[quote(x), quote(x)]`},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			require.Equal(t, subtest.expected, obj.Inspect())
+		})
+	}
+
+	for input, expected := range map[string]string{
+		`quote(1, 2)`:                     "quote expected 1 argument but received 2",
+		`quote(unquote(len))`:             "cannot unquote BUILTIN",
+		`quote(unquote(nope))`:            "identifier not found: nope",
+		`quote(unquote(1, 2))`:            "unquote expected 1 argument but received 2",
+		`let m = macro(x) { }`:            "macros can only be defined at the top level",
+		`fn() { return macro(x) { }; }()`: "macros can only be defined at the top level",
+	} {
+		testResult(t, testParseInput(input), expected)
+	}
+}
+
+func TestEval_MacroExpansion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`let infix = macro() { return quote(1 + 2); }; infix()`,
+			`(1 + 2)`,
+		},
+		{
+			`let reverse = macro(a, b) { return quote(unquote(b) - unquote(a)); }; reverse(2 + 2, 10 - 5)`,
+			`((10 - 5) - (2 + 2))`,
+		},
+		{
+			`let unless = macro(cond, then, otherwise) {
+    return quote(if (!(unquote(cond))) { unquote(then) } else { unquote(otherwise) });
+};
+unless(10 > 5, print("not greater"), print("greater"))`,
+			`if(!(10 > 5)) print("not greater")else print("greater")`,
+		},
+		{
+			`let twice = macro(x) { return quote([unquote(x), unquote(x)]); }; twice(twice(1))`,
+			`[[1, 1], [1, 1]]`,
+		},
+		{
+			`let id = macro(x) { return x; }; let wrap = macro(x) { return quote(id(unquote(x))); }; wrap(5)`,
+			`5`,
+		},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			expanded, _, err := testExpand(t, subtest.input)
+			require.Nil(t, err)
+			require.Equal(t, subtest.expected, expanded.String())
+		})
+	}
+}
+
+func TestEval_MacroErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let m = macro(x) { return 1; }; m(2)`, "macro m must return a quote, not int"},
+		{`let m = macro(x) { return x; }; m()`, "macro m expected 1 arguments but received 0"},
+		{`let m = macro(x) { return x; }; m(x = 1)`, "unexpected keyword argument x"},
+		{`let m = macro(x) { throw "bad rule"; }; m(1)`, "bad rule"},
+		{`let m = macro() { return quote(m()); }; m()`, "expansion of macro m is too deep"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			_, _, err := testExpand(t, subtest.input)
+			require.NotNil(t, err)
+			require.Contains(t, err.Message, subtest.expected)
+		})
+	}
+}
+
+func TestEval_MacroHygiene(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		// tmp in the expansion doesn't shadow tmp at the call site
+		{`let add_one = macro(x) {
+    return quote(fn() { let tmp = 1; return unquote(x) + tmp; }());
+};
+let tmp = 10;
+add_one(tmp)`, 11},
+		{`let plus_head = macro(xs, other) {
+    return quote(match (unquote(xs)) { [h, ...rest] => h + unquote(other), _ => unquote(other) });
+};
+let h = 100;
+plus_head([1, 2], h)`, 101},
+		{`let each = macro(xs, body) {
+    return quote([unquote(body) for x in unquote(xs)]);
+};
+let x = 10;
+each([1, 2], x + 1)`, []interface{}{11, 11}},
+		{`let square = macro(x) { return quote(unquote(x) * unquote(x)); }; square(3)`, 9},
+		{`let m = macro(x) { return quote(len(unquote(x))); }; let len = fn(x) { return 0; }; m([1])`, 0},
+		// keyword arguments follow the renamed parameters they name
+		{`let double = macro(e) { return quote(fn(n) { return n * 2; }(n = unquote(e))); }; double(4)`, 8},
+		{`let m = macro(x) { return quote(fn(n) { return n - unquote(x); }(n = 10)); }; let f = fn(n) { return n; }; m(f(n = 3))`, 7},
+		{`let m = macro(xs) { return quote(parallel_map(fn(x) { return x * 2; }, unquote(xs), workers = 1)); }; m([1, 2])`, []interface{}{2, 4}},
+		{`let m = macro(xs) {
+    return quote(fn() { let workers = 2; return parallel_map(fn(x) { return x * 2; }, unquote(xs), workers = workers); }());
+};
+m([1, 2])`, []interface{}{2, 4}},
+		{`let f = fn(n) { return n; };
+let m = macro(x) { return quote(fn() { let n = unquote(x); return f(n = n + 1); }()); };
+m(1)`, 2},
+		{`let m = macro(x) { return quote(fn() { let g = fn(n) { return n * 3; }; return g(n = unquote(x)); }()); }; m(2)`, 6},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			expanded, env, err := testExpand(t, subtest.input)
+			require.Nil(t, err)
+			testResult(t, Eval(expanded, env), subtest.expected)
+		})
+	}
+}
+
+func TestEval_MacroHygieneRenamesBindings(t *testing.T) {
+	expanded, _, err := testExpand(t, `let m = macro(x) {
+    return quote(fn(y) { let z = unquote(x); return y + z + w; });
+};
+m(y)`)
+	require.Nil(t, err)
+	fn := expanded.(*ast.Program).Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteralExpression)
+	param := fn.Parameters[0].Value
+	require.NotEqual(t, "y", param)
+	let := fn.Body.Statements[0].(*ast.LetStatement)
+	require.NotEqual(t, "z", let.Name.Value)
+	// the argument and free names keep their names
+	require.Equal(t, "y", let.Value.String())
+	require.Contains(t, fn.Body.Statements[1].String(), "w")
+	require.Contains(t, fn.Body.Statements[1].String(), param)
+}
+
+func TestEval_MacroHelp(t *testing.T) {
+	expanded, env, err := testExpand(t, `/// Doubles x.
+let twice = macro(x) { return quote(unquote(x) * 2); };
+help(twice)`)
+	require.Nil(t, err)
+	testResult(t, Eval(expanded, env), "Doubles x.")
+}
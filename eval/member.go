@@ -0,0 +1,143 @@
+package eval
+
+import (
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/object"
+)
+
+// keyed is implemented by objects whose members are looked up by name,
+// such as maps
+type keyed interface {
+	Get(key object.Object) (object.Object, bool)
+}
+
+// shortCircuit is the value of a chain of member, index, slice and call
+// expressions once an optional link in it finds its receiver null. The
+// rest of the chain is skipped and the chain as a whole is null.
+type shortCircuit struct{ *object.Null }
+
+var skipChain object.Object = &shortCircuit{object.NullValue}
+
+// evalChain evaluates node as a link in a chain, returning skipChain when
+// the chain was short circuited
+func evalChain(node ast.Expression, env *object.Env) object.Object {
+	switch n := node.(type) {
+	case *ast.MemberExpression:
+		return evalMemberExpression(n, env)
+	case *ast.IndexExpression:
+		return evalIndex(n, env)
+	case *ast.SliceExpression:
+		return evalSliceExpression(n, env)
+	case *ast.CallExpression:
+		return evalCallExpression(n, env)
+	default:
+		return Eval(node, env)
+	}
+}
+
+// endChain gives the value of a whole chain
+func endChain(obj object.Object) object.Object {
+	if obj == skipChain {
+		return object.NullValue
+	}
+	return obj
+}
+
+func evalIndex(n *ast.IndexExpression, env *object.Env) object.Object {
+	items := evalChain(n.Left, env)
+	if items == skipChain || isUnwinding(items) {
+		return items
+	}
+	if n.Optional && items == object.NullValue {
+		return skipChain
+	}
+	rank := Eval(n.Index, env)
+	if isUnwinding(rank) {
+		return rank
+	}
+	return evalIndexExpression(env.Context(), items, rank)
+}
+
+func evalMemberExpression(n *ast.MemberExpression, env *object.Env) object.Object {
+	obj := evalChain(n.Object, env)
+	if obj == skipChain || isUnwinding(obj) {
+		return obj
+	}
+	if n.Optional && obj == object.NullValue {
+		return skipChain
+	}
+	name := n.Property.Value
+	if value, ok := lookupMember(obj, name); ok {
+		return value
+	}
+	switch obj.(type) {
+	case *object.Instance, *object.EnumType, *object.EnumValue, *object.Module:
+		return &object.Error{
+			ErrorType: object.ErrorTypeKeyError,
+			Message:   fmt.Sprintf("%s has no member %s", memberOwner(obj), name),
+		}
+	}
+	if k, ok := obj.(keyed); ok {
+		// keys shadow the methods of the map
+		if value, ok := k.Get(&object.String{Value: name}); ok {
+			return value
+		}
+		if method, ok := object.BoundMethod(obj, name); ok {
+			return method
+		}
+		return &object.Error{
+			ErrorType: object.ErrorTypeKeyError,
+			Message:   fmt.Sprintf("%s has no member %s", obj.Type(), name),
+		}
+	}
+	if method, ok := object.BoundMethod(obj, name); ok {
+		return method
+	}
+	return object.NewTypeError("%s has no member %s", obj.Type(), name)
+}
+
+// lookupMember returns the member of a user defined type
+func lookupMember(obj object.Object, name string) (object.Object, bool) {
+	switch o := obj.(type) {
+	case *object.Instance:
+		if value, ok := o.Field(name); ok {
+			return value, true
+		}
+		if method, ok := o.Method(name); ok {
+			return method, true
+		}
+	case *object.EnumType:
+		return o.Member(name)
+	case *object.EnumValue:
+		return o.Field(name)
+	case *object.Module:
+		return o.Member(name)
+	}
+	return nil, false
+}
+
+// memberOwner names obj in errors about its members
+func memberOwner(obj object.Object) string {
+	switch o := obj.(type) {
+	case *object.EnumType:
+		return o.Name
+	case *object.Module:
+		return "module " + o.Name
+	default:
+		return obj.Type().String()
+	}
+}
+
+// setMember assigns to a field of a struct instance or a string key of a
+// map
+func setMember(obj object.Object, name string, value object.Object) object.Object {
+	switch target := obj.(type) {
+	case *object.Instance:
+		return target.SetField(name, value)
+	case *object.Map:
+		return object.SetIndex(target, &object.String{Value: name}, value)
+	default:
+		return object.NewTypeError("%s has no member %s", obj.Type(), name)
+	}
+}
@@ -0,0 +1,236 @@
+package eval
+
+import (
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/lexer"
+	"mitchlang/object"
+	"mitchlang/parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Extension is added to import paths that don't have one
+const Extension = ".mitch"
+
+// Importer loads modules from source files. Import paths are resolved
+// against the directory of the importing file and then each directory in
+// SearchPath. Each file is evaluated once and the module is shared by
+// every file that imports it. An Importer may be used by several tasks at
+// once.
+type Importer struct {
+	SearchPath []string
+
+	mu      sync.Mutex
+	modules map[string]*object.Module
+	// allowed lists the visible native modules, or is nil when every
+	// registered module is visible
+	allowed map[string]bool
+	// loading maps the file of each module being evaluated to the file
+	// that imported it
+	loading map[string]string
+	// pending holds a channel for each module being evaluated which is
+	// closed once evaluation has finished
+	pending map[string]chan struct{}
+}
+
+func NewImporter(searchPath ...string) *Importer {
+	return &Importer{
+		SearchPath: searchPath,
+		modules:    map[string]*object.Module{},
+		loading:    map[string]string{},
+		pending:    map[string]chan struct{}{},
+	}
+}
+
+// defaultImporter loads modules imported from scopes that don't belong to
+// a module, such as the repl, relative to the working directory
+var defaultImporter = NewImporter()
+
+// Import returns the native module registered as path or the module at
+// path, evaluating it the first time it is imported
+func (im *Importer) Import(path string, from *object.Module) object.Object {
+	if module, ok := im.native(path); ok {
+		return module
+	}
+	file, err := im.resolve(path, from)
+	if err != nil {
+		return err
+	}
+	importer := ""
+	if from != nil {
+		importer = from.Path
+	}
+
+	im.mu.Lock()
+	if module, ok := im.modules[file]; ok {
+		im.mu.Unlock()
+		return module
+	}
+	if done, ok := im.pending[file]; ok {
+		if err := im.checkCycle(file, importer); err != nil {
+			im.mu.Unlock()
+			return err
+		}
+		// another task is evaluating the module
+		im.mu.Unlock()
+		<-done
+		im.mu.Lock()
+		module, ok := im.modules[file]
+		im.mu.Unlock()
+		if !ok {
+			return &object.Error{
+				ErrorType: object.ErrorTypeImportError,
+				Message:   fmt.Sprintf("cannot import %s: module failed to load", path),
+			}
+		}
+		return module
+	}
+	done := make(chan struct{})
+	im.pending[file] = done
+	im.loading[file] = importer
+	im.mu.Unlock()
+
+	module, err := im.load(path, file)
+
+	im.mu.Lock()
+	delete(im.pending, file)
+	delete(im.loading, file)
+	if module != nil {
+		im.modules[file] = module
+	}
+	close(done)
+	im.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return module
+}
+
+// checkCycle returns an error if file is imported, directly or not, by
+// the module being evaluated from importer. The caller must hold im.mu.
+func (im *Importer) checkCycle(file string, importer string) *object.Error {
+	chain := []string{}
+	for f := importer; f != ""; f = im.loading[f] {
+		chain = append([]string{filepath.Base(f)}, chain...)
+		if f == file {
+			chain = append(chain, filepath.Base(file))
+			return &object.Error{
+				ErrorType: object.ErrorTypeImportError,
+				Message:   "circular import: " + strings.Join(chain, " -> "),
+			}
+		}
+	}
+	return nil
+}
+
+// load evaluates the source file of a module
+func (im *Importer) load(path string, file string) (*object.Module, *object.Error) {
+	source, readErr := os.ReadFile(file)
+	if readErr != nil {
+		return nil, &object.Error{
+			ErrorType: object.ErrorTypeImportError,
+			Message:   fmt.Sprintf("cannot import %s: %s", path, readErr),
+		}
+	}
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		return nil, &object.Error{
+			ErrorType: object.ErrorTypeImportError,
+			Message:   fmt.Sprintf("cannot import %s: %s", path, strings.Join(p.Errors(), "; ")),
+		}
+	}
+
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	module := object.NewModule(name, file, im)
+	DefineMacros(program, module.Env)
+	expanded, err := ExpandMacros(program, module.Env)
+	if err != nil {
+		err.Trace = append(err.Trace, fmt.Sprintf("import %q", path))
+		return nil, err
+	}
+	if out := Eval(expanded, module.Env); isError(out) {
+		err := out.(*object.Error)
+		err.Trace = append(err.Trace, fmt.Sprintf("import %q", path))
+		return nil, err
+	}
+	return module, nil
+}
+
+// resolve returns the absolute path of the file imported as path
+func (im *Importer) resolve(path string, from *object.Module) (string, *object.Error) {
+	if filepath.Ext(path) == "" {
+		path += Extension
+	}
+	candidates := []string{path}
+	if !filepath.IsAbs(path) {
+		dir := "."
+		if from != nil && from.Path != "" {
+			dir = filepath.Dir(from.Path)
+		}
+		candidates = []string{filepath.Join(dir, path)}
+		for _, searchDir := range im.SearchPath {
+			candidates = append(candidates, filepath.Join(searchDir, path))
+		}
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			abs, err := filepath.Abs(candidate)
+			if err != nil {
+				return "", &object.Error{ErrorType: object.ErrorTypeImportError, Message: err.Error()}
+			}
+			return abs, nil
+		}
+	}
+	return "", &object.Error{
+		ErrorType: object.ErrorTypeImportError,
+		Message:   fmt.Sprintf("module not found: %s", path),
+	}
+}
+
+func evalImportStatement(n *ast.ImportStatement, env *object.Env) object.Object {
+	module := importerFor(env).Import(n.Path.Value, env.Module())
+	if isError(module) {
+		return module
+	}
+	if out := env.Set(n.Alias.Value, module); isError(out) {
+		return out
+	}
+	return object.NullValue
+}
+
+func evalExportStatement(n *ast.ExportStatement, env *object.Env) object.Object {
+	module := env.Module()
+	if module == nil || module.Env != env {
+		return &object.Error{Message: "export outside of module scope"}
+	}
+	out := Eval(n.Statement, env)
+	if isUnwinding(out) {
+		return out
+	}
+	for _, name := range declaredNames(n.Statement) {
+		module.Export(name)
+	}
+	return out
+}
+
+// declaredNames returns the names bound by a declaration
+func declaredNames(statement ast.Statement) []string {
+	switch s := statement.(type) {
+	case *ast.LetStatement:
+		if s.Pattern != nil {
+			return patternNames(s.Pattern)
+		}
+		return []string{s.Name.Value}
+	case *ast.StructStatement:
+		return []string{s.Name.Value}
+	case *ast.EnumStatement:
+		return []string{s.Name.Value}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package eval
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"mitchlang/lexer"
+	"mitchlang/object"
+	"mitchlang/parser"
+)
+
+// testModules writes files into a temporary directory and returns its
+// path
+func testModules(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(source), 0o644))
+	}
+	return dir
+}
+
+func testRunModule(t *testing.T, importer *Importer, path string, in string) object.Object {
+	p := parser.New(lexer.New(in))
+	program := p.ParseProgram()
+	require.Empty(t, p.Errors())
+	return Eval(program, object.NewModule("main", path, importer).Env)
+}
+
+func TestEval_Import(t *testing.T) {
+	dir := testModules(t, map[string]string{
+		"lib/strings.mitch": `import "helper";
+export let shout = fn(s) { return helper.bang(s.upper()); };
+export const [first, second] = ["a", "b"];
+export struct Pair { a, b };
+let private = 1;`,
+		"lib/helper.mitch":  `export let bang = fn(s) { return s + "!"; };`,
+		"state.mitch":       `export let calls = [];`,
+		"a.mitch":           `import "state"; state.calls.push("a");`,
+		"b.mitch":           `import "state"; state.calls.push("b");`,
+		"cycle/x.mitch":     `import "y";`,
+		"cycle/y.mitch":     `import "x";`,
+		"broken.mitch":      `let = 1;`,
+		"raises.mitch":      `throw "boom";`,
+		"vendor/util.mitch": `export let answer = 42;`,
+		"rules.mitch": `let twice = macro(x) { return quote(unquote(x) * 2); };
+export let limit = twice(21);`,
+		"badmacro.mitch": `let m = macro() { return 1; }; m();`,
+	})
+	main := filepath.Join(dir, "main.mitch")
+	importer := NewImporter(filepath.Join(dir, "vendor"))
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`import "lib/strings" as s; s.shout("hi")`, "HI!"},
+		{`import "lib/strings"; strings.shout("a")`, "A!"},
+		{`import "lib/strings.mitch" as s; s.second`, "b"},
+		{`import "lib/strings" as s; s.Pair(1, 2).b`, 2},
+		{`import "lib/strings" as s; s.private`, "module strings has no member private"},
+		{`import "lib/strings" as s; s.helper`, "module strings has no member helper"},
+		{`import "lib/strings" as s; s?.private ?? "hidden"`, "module strings has no member private"},
+		{`import "util"; util.answer`, 42},
+		{`import "a"; import "b"; import "state"; state.calls`, []interface{}{"a", "b"}},
+		{`import "cycle/x"`, "circular import: x.mitch -> y.mitch -> x.mitch"},
+		{`import "missing"`, "module not found: missing.mitch"},
+		{`import "broken"`, "cannot import broken"},
+		{`import "raises"`, "boom"},
+		{`import "rules"; rules.limit`, 42},
+		{`import "badmacro"`, "macro m must return a quote, not int"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testRunModule(t, importer, main, subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_ImportCaches(t *testing.T) {
+	dir := testModules(t, map[string]string{"m.mitch": `export let xs = [];`})
+	importer := NewImporter()
+	main := filepath.Join(dir, "main.mitch")
+
+	first := testRunModule(t, importer, main, `import "m"; m`)
+	second := testRunModule(t, importer, main, `import "./m.mitch" as other; other`)
+	require.Same(t, first, second)
+}
+
+func TestEval_Export(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`export let x = 1;`, "export outside of module scope"},
+		{`let f = fn() { export let x = 1; }; f()`, "export outside of module scope"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+
+	obj := testRunModule(t, NewImporter(), "main.mitch", `let f = fn() { export let x = 1; }; f()`)
+	testResult(t, obj, "export outside of module scope")
+}
@@ -0,0 +1,87 @@
+package eval
+
+import (
+	"fmt"
+	"mitchlang/object"
+	"sync"
+)
+
+// NativeModule builds the members of a module implemented in Go. It is
+// called the first time an interpreter imports the module.
+type NativeModule func() map[string]object.Object
+
+var (
+	nativeMu      sync.RWMutex
+	nativeModules = map[string]NativeModule{}
+)
+
+func init() {
+	RegisterModule("math", object.MathModule)
+	RegisterModule("strings", object.StringsModule)
+	RegisterModule("time", object.TimeModule)
+	RegisterModule("os", object.OSModule)
+	RegisterModule("code", codeModule)
+}
+
+// RegisterModule makes a native module available to scripts as
+// `import "name"`. It panics if a module is registered twice under the
+// same name.
+func RegisterModule(name string, module NativeModule) {
+	nativeMu.Lock()
+	defer nativeMu.Unlock()
+	if _, ok := nativeModules[name]; ok {
+		panic(fmt.Sprintf("eval: module %s registered twice", name))
+	}
+	nativeModules[name] = module
+}
+
+func lookupNativeModule(name string) (NativeModule, bool) {
+	nativeMu.RLock()
+	defer nativeMu.RUnlock()
+	module, ok := nativeModules[name]
+	return module, ok
+}
+
+// AllowModules restricts the native modules visible to scripts run by
+// the importer to names. By default every registered module is visible.
+func (im *Importer) AllowModules(names ...string) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	im.allowed = make(map[string]bool, len(names))
+	for _, name := range names {
+		im.allowed[name] = true
+	}
+}
+
+// native returns the native module registered as name, building it the
+// first time it is imported through im
+func (im *Importer) native(name string) (*object.Module, bool) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	if im.allowed != nil && !im.allowed[name] {
+		return nil, false
+	}
+	build, ok := lookupNativeModule(name)
+	if !ok {
+		return nil, false
+	}
+	key := "native:" + name
+	if module, ok := im.modules[key]; ok {
+		return module, true
+	}
+	module := object.NewModule(name, "", im)
+	for member, value := range build() {
+		module.Env.Set(member, value)
+		module.Export(member)
+	}
+	im.modules[key] = module
+	return module, true
+}
+
+// importerFor returns the importer of the module env belongs to
+func importerFor(env *object.Env) object.Importer {
+	if module := env.Module(); module != nil && module.Importer != nil {
+		return module.Importer
+	}
+	return defaultImporter
+}
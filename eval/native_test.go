@@ -0,0 +1,90 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"mitchlang/object"
+)
+
+func TestEval_NativeModules(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`import "math"; math.sqrt(17)`, 4},
+		{`math.sqrt(16)`, 4},
+		{`import "math" as m; m.pow(2, 10)`, 1024},
+		{`math.abs(-3)`, 3},
+		{`[math.min(3, 1, 2), math.max(3, 1, 2)]`, []interface{}{1, 3}},
+		{`math.max("a", "b")`, "b"},
+		{`math.min()`, "math.min expected at least 1 positional argument"},
+		{`math.sqrt(-1)`, "math.sqrt of negative number"},
+		{`math.sqrt(9223372036854775806)`, 3037000499},
+		{`math.sqrt(9223372036854775807)`, 3037000499},
+		{`[math.sqrt(0), math.sqrt(1), math.sqrt(3), math.sqrt(4)]`, []interface{}{0, 1, 1, 2}},
+		{`[math.pow(3, 0), math.pow(3, 5), math.pow(-2, 3)]`, []interface{}{1, 243, -8}},
+		{`math.pow(1, 9223372036854775807)`, 1},
+		{`math.pow(2, -1)`, "math.pow with negative exponent"},
+		{`math.sqrt("a")`, "math.sqrt expected positional argument 1 to be type int but received type str"},
+		{`math.nope`, "module math has no member nope"},
+		{`strings.join(["a", "b"], ", ")`, "a, b"},
+		{`strings.join([1], "")`, "strings.join expected a list of str, found int"},
+		{`strings.repeat("ab", 3)`, "ababab"},
+		{`strings.repeat("", 9223372036854775807)`, ""},
+		{`strings.repeat("ab", 9223372036854775807)`, "strings.repeat result is too long"},
+		{`strings.repeat("ab", -1)`, "strings.repeat with negative count"},
+		{`strings.count("banana", "a")`, 3},
+		{`time.now() > 0`, true},
+		{`time.sleep(0)`, nil},
+		{`os.getenv("MITCHLANG_TEST_UNSET")`, nil},
+		{`len(os.args) > 0`, true},
+		{`iter.next`, "identifier not found: iter"},
+		{`code.eval`, "module code has no member eval"},
+		{`let math = 1; math`, 1},
+		{`import "math"; math is math`, true},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.input, func(t *testing.T) {
+			obj := testParseInput(subtest.input)
+			testResult(t, obj, subtest.expected)
+		})
+	}
+}
+
+func TestEval_RegisterModule(t *testing.T) {
+	loads := 0
+	RegisterModule("test_counter", func() map[string]object.Object {
+		loads++
+		return map[string]object.Object{"answer": &object.Integer{Value: 42}}
+	})
+	require.Panics(t, func() {
+		RegisterModule("test_counter", func() map[string]object.Object { return nil })
+	})
+	require.Equal(t, 0, loads)
+
+	importer := NewImporter()
+	testResult(t, testRunModule(t, importer, "main.mitch", `import "test_counter" as c; c.answer`), 42)
+	testResult(t, testRunModule(t, importer, "main.mitch", `test_counter.answer`), 42)
+	require.Equal(t, 1, loads)
+
+	testResult(t, testRunModule(t, NewImporter(), "main.mitch", `test_counter.answer`), 42)
+	require.Equal(t, 2, loads)
+}
+
+func TestEval_AllowModules(t *testing.T) {
+	importer := NewImporter()
+	importer.AllowModules("math")
+
+	testResult(t, testRunModule(t, importer, "main.mitch", `math.abs(-1)`), 1)
+	testResult(t, testRunModule(t, importer, "main.mitch", `os.getenv("HOME")`), "identifier not found: os")
+	testResult(t, testRunModule(t, importer, "main.mitch", `import "os"`), "module not found: os.mitch")
+
+	// code run by eval can only reach the modules of its caller
+	importer.AllowModules("math", "code")
+	testResult(t, testRunModule(t, importer, "main.mitch", `eval("math.abs(-1)")`), 1)
+	testResult(t, testRunModule(t, importer, "main.mitch", `eval("os.exit(3)")`), "identifier not found: os")
+	testResult(t, testRunModule(t, importer, "main.mitch", `eval("os.getenv(name)", code.env({"name": "HOME"}))`), "identifier not found: os")
+	testResult(t, testRunModule(t, importer, "main.mitch", `parallel_map(eval, ["math.abs(-1)"])`), "identifier not found: math")
+}
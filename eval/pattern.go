@@ -0,0 +1,270 @@
+package eval
+
+import (
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/object"
+)
+
+func evalMatchExpression(n *ast.MatchExpression, env *object.Env) object.Object {
+	subject := Eval(n.Subject, env)
+	if isUnwinding(subject) {
+		return subject
+	}
+	for _, arm := range n.Arms {
+		bindings, err := matchPattern(arm.Pattern, subject, env)
+		if err != nil {
+			return err
+		}
+		if bindings == nil {
+			continue
+		}
+		armEnv := env.Push()
+		for _, name := range bindings.names {
+			armEnv.Set(name, bindings.values[name])
+		}
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isUnwinding(guard) {
+				return guard
+			}
+			if guard != object.True {
+				continue
+			}
+		}
+		return Eval(arm.Body, armEnv)
+	}
+	return &object.Error{
+		ErrorType: object.ErrorTypeMatchError,
+		Message:   fmt.Sprintf("no pattern matched value %s", subject.Inspect()),
+	}
+}
+
+// matchPattern returns the names bound by matching value against
+// pattern, or nil bindings when value doesn't match. An error is only
+// returned when the pattern itself fails, for example when a literal or
+// map key in it can't be evaluated.
+func matchPattern(pattern ast.Pattern, value object.Object, env *object.Env) (*bindings, *object.Error) {
+	b := newBindings()
+	if bindPattern(pattern, value, b, env) != nil {
+		return nil, b.err
+	}
+	return b, nil
+}
+
+// bindings holds the names bound while matching a pattern, in the order
+// they appear in the pattern
+type bindings struct {
+	names  []string
+	values map[string]object.Object
+	// err is the error the pattern itself failed with, as opposed to the
+	// value not fitting the pattern
+	err *object.Error
+}
+
+func newBindings() *bindings {
+	return &bindings{values: map[string]object.Object{}}
+}
+
+// bind binds name to value, failing if the pattern already bound name
+func (b *bindings) bind(name string, value object.Object) *object.Error {
+	if _, ok := b.values[name]; ok {
+		return b.fail(&object.Error{
+			ErrorType: object.ErrorTypeSyntaxError,
+			Message:   fmt.Sprintf("name %s bound more than once in pattern", name),
+		})
+	}
+	b.names = append(b.names, name)
+	b.values[name] = value
+	return nil
+}
+
+// fail records err as a failure of the pattern rather than a mismatch
+// and returns it
+func (b *bindings) fail(err *object.Error) *object.Error {
+	b.err = err
+	return err
+}
+
+// patternNames returns the names bound by pattern
+func patternNames(pattern ast.Pattern) []string {
+	switch p := pattern.(type) {
+	case *ast.BindingPattern:
+		return []string{p.Name.Value}
+	case *ast.ListPattern:
+		names := []string{}
+		for _, element := range p.Elements {
+			names = append(names, patternNames(element)...)
+		}
+		if p.Rest != nil {
+			names = append(names, patternNames(p.Rest)...)
+		}
+		return names
+	case *ast.MapPattern:
+		names := []string{}
+		for _, value := range p.Values {
+			names = append(names, patternNames(value)...)
+		}
+		return names
+	case *ast.VariantPattern:
+		names := []string{}
+		for _, field := range p.Fields {
+			names = append(names, patternNames(field)...)
+		}
+		return names
+	default:
+		return nil
+	}
+}
+
+// destructure binds the names in pattern to the matching parts of value
+// in env, or returns an error describing why value doesn't fit pattern.
+// Nothing is bound when the value doesn't fit.
+func destructure(pattern ast.Pattern, value object.Object, env *object.Env) *object.Error {
+	return destructureWith(pattern, value, env, false)
+}
+
+// destructureWith is destructure declaring the names as constants when
+// constant is set. Nothing is bound if any of the names can't be
+// declared.
+func destructureWith(pattern ast.Pattern, value object.Object, env *object.Env, constant bool) *object.Error {
+	b := newBindings()
+	if err := bindPattern(pattern, value, b, env); err != nil {
+		return err
+	}
+	for _, name := range b.names {
+		if err := env.CheckDeclare(name, constant); err != nil {
+			return err
+		}
+	}
+	declare := env.Set
+	if constant {
+		declare = env.SetConst
+	}
+	for _, name := range b.names {
+		if err, ok := declare(name, b.values[name]).(*object.Error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindParameters binds args to the parameters of fn in env, failing if
+// two parameters bind the same name. Any number of parameters may be
+// named _.
+func bindParameters(fn *object.Function, args []object.Object, env *object.Env) *object.Error {
+	params := newBindings()
+	for k, param := range fn.Parameters {
+		b := newBindings()
+		if k < len(fn.Patterns) && fn.Patterns[k] != nil {
+			if err := bindPattern(fn.Patterns[k], args[k], b, env); err != nil {
+				return err
+			}
+		} else {
+			b.bind(param.Value, args[k])
+		}
+		for _, name := range b.names {
+			if _, ok := params.values[name]; ok && name != "_" {
+				return &object.Error{
+					ErrorType: object.ErrorTypeSyntaxError,
+					Message:   fmt.Sprintf("name %s bound more than once in parameters", name),
+				}
+			}
+			params.names = append(params.names, name)
+			params.values[name] = b.values[name]
+		}
+	}
+	for _, name := range params.names {
+		if err, ok := env.Set(name, params.values[name]).(*object.Error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+func bindPattern(
+	pattern ast.Pattern,
+	value object.Object,
+	b *bindings,
+	env *object.Env,
+) *object.Error {
+	switch p := pattern.(type) {
+	case *ast.WildcardPattern:
+		return nil
+	case *ast.BindingPattern:
+		return b.bind(p.Name.Value, value)
+	case *ast.LiteralPattern:
+		literal := Eval(p.Value, env)
+		if err, ok := literal.(*object.Error); ok {
+			return b.fail(err)
+		}
+		if literal.Type() != value.Type() || object.Eq(env.Context(), literal, value) != object.True {
+			return &object.Error{
+				ErrorType: object.ErrorTypeValueError,
+				Message:   fmt.Sprintf("expected %s, got %s", literal.Inspect(), value.Inspect()),
+			}
+		}
+		return nil
+	case *ast.ListPattern:
+		list, ok := value.(*object.List)
+		if !ok {
+			return object.NewTypeError("cannot destructure %s as %s", value.Type(), object.TypeList)
+		}
+		values := list.Values()
+		if p.Rest == nil && len(values) != len(p.Elements) {
+			return &object.Error{
+				ErrorType: object.ErrorTypeValueError,
+				Message: fmt.Sprintf(
+					"expected %d values to unpack, got %d",
+					len(p.Elements),
+					len(values),
+				),
+			}
+		}
+		if len(values) < len(p.Elements) {
+			return &object.Error{
+				ErrorType: object.ErrorTypeValueError,
+				Message: fmt.Sprintf(
+					"expected at least %d values to unpack, got %d",
+					len(p.Elements),
+					len(values),
+				),
+			}
+		}
+		for k, element := range p.Elements {
+			if err := bindPattern(element, values[k], b, env); err != nil {
+				return err
+			}
+		}
+		if p.Rest != nil {
+			return bindPattern(p.Rest, object.NewList(values[len(p.Elements):]...), b, env)
+		}
+		return nil
+	case *ast.MapPattern:
+		m, ok := value.(*object.Map)
+		if !ok {
+			return object.NewTypeError("cannot destructure %s as %s", value.Type(), object.TypeMap)
+		}
+		for k, key := range p.Keys {
+			keyObj := Eval(key, env)
+			if err, ok := keyObj.(*object.Error); ok {
+				return b.fail(err)
+			}
+			item, ok := m.Get(keyObj)
+			if !ok {
+				return &object.Error{
+					ErrorType: object.ErrorTypeKeyError,
+					Message:   fmt.Sprintf("key not found: %s", keyObj.Inspect()),
+				}
+			}
+			if err := bindPattern(p.Values[k], item, b, env); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *ast.VariantPattern:
+		return bindVariantPattern(p, value, b, env)
+	default:
+		return b.fail(&object.Error{Message: fmt.Sprintf("unknown pattern %s", pattern)})
+	}
+}
@@ -0,0 +1,128 @@
+package eval
+
+import (
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/object"
+	"mitchlang/token"
+)
+
+// quote(x) returns the code x without evaluating it, and unquote(x) inside
+// quoted code is replaced by the value of x
+const (
+	quoteName   = "quote"
+	unquoteName = "unquote"
+)
+
+// isCallOf reports whether node is a call of the function called name
+func isCallOf(node ast.Node, name string) bool {
+	call, ok := node.(*ast.CallExpression)
+	if !ok {
+		return false
+	}
+	ident, ok := call.Function.(*ast.Identifier)
+	return ok && ident.Value == name
+}
+
+// evalQuote returns a copy of node with each unquote call inside it
+// replaced by the code of its value. During macro expansion the names
+// bound by the quoted code are renamed first.
+func evalQuote(n *ast.CallExpression, env *object.Env) object.Object {
+	if len(n.Arguments) != 1 || len(n.Keywords) > 0 {
+		return object.NewTypeError("quote expected 1 argument but received %d", len(n.Arguments)+len(n.Keywords))
+	}
+	node := ast.Copy(n.Arguments[0])
+
+	// unquote calls are set aside first so that only names written in the
+	// quoted code are renamed, and not those in the code spliced into it
+	unquotes := map[*ast.Identifier]*ast.CallExpression{}
+	node = ast.Modify(node, func(node ast.Node) ast.Node {
+		if !isCallOf(node, unquoteName) {
+			return node
+		}
+		call := node.(*ast.CallExpression)
+		placeholder := &ast.Identifier{Token: call.Token, Value: unquoteName}
+		unquotes[placeholder] = call
+		return placeholder
+	})
+	if exp := currentExpansion(env); exp != nil {
+		node = exp.rename(node, unquotes)
+	}
+
+	var err object.Object
+	node = ast.Modify(node, func(node ast.Node) ast.Node {
+		call, ok := unquotes[asIdentifier(node)]
+		if !ok || err != nil {
+			return node
+		}
+		if len(call.Arguments) != 1 || len(call.Keywords) > 0 {
+			err = object.NewTypeError("unquote expected 1 argument but received %d", len(call.Arguments)+len(call.Keywords))
+			return node
+		}
+		value := Eval(call.Arguments[0], env)
+		if isUnwinding(value) {
+			err = value
+			return node
+		}
+		out, unquoteErr := unquote(value)
+		if unquoteErr != nil {
+			err = unquoteErr
+			return node
+		}
+		return out
+	})
+	if err != nil {
+		return err
+	}
+	return &object.Quote{Node: node}
+}
+
+func asIdentifier(node ast.Node) *ast.Identifier {
+	ident, _ := node.(*ast.Identifier)
+	return ident
+}
+
+// unquote returns the code for obj
+func unquote(obj object.Object) (ast.Node, *object.Error) {
+	switch obj := obj.(type) {
+	case *object.Quote:
+		return obj.Node, nil
+	case *object.Integer:
+		return &ast.IntegerLiteral{
+			Token: token.NewFromString(token.Int, fmt.Sprint(obj.Value)),
+			Value: obj.Value,
+		}, nil
+	case *object.Boolean:
+		return &ast.Boolean{
+			Token: token.NewIdentifier(fmt.Sprint(obj.Value)),
+			Value: obj.Value,
+		}, nil
+	case *object.String:
+		return &ast.StringLiteral{
+			Token: token.NewFromString(token.String, obj.Value),
+			Value: obj.Value,
+		}, nil
+	case *object.Null:
+		return &ast.NullLiteral{Token: token.NewIdentifier("null")}, nil
+	case *object.List:
+		values := obj.Values()
+		list := &ast.ListExpression{
+			Token: token.New(token.LBracket, '['),
+			Items: make([]ast.Expression, 0, len(values)),
+		}
+		for _, value := range values {
+			item, err := unquote(value)
+			if err != nil {
+				return nil, err
+			}
+			exp, ok := item.(ast.Expression)
+			if !ok {
+				return nil, object.NewTypeError("cannot unquote statement %s", item)
+			}
+			list.Items = append(list.Items, exp)
+		}
+		return list, nil
+	default:
+		return nil, object.NewTypeError("cannot unquote %s", obj.Type())
+	}
+}
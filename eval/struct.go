@@ -0,0 +1,31 @@
+package eval
+
+import (
+	"mitchlang/ast"
+	"mitchlang/object"
+)
+
+func evalStructStatement(n *ast.StructStatement, env *object.Env) object.Object {
+	structType := &object.StructType{
+		Name:    n.Name.Value,
+		Fields:  make([]string, 0, len(n.Fields)),
+		Methods: make(map[string]*object.Function, len(n.Methods)),
+	}
+	for _, field := range n.Fields {
+		structType.Fields = append(structType.Fields, field.Value)
+	}
+	for _, method := range n.Methods {
+		structType.Methods[method.Name.Value] = &object.Function{
+			Parameters: method.Function.Parameters,
+			Patterns:   method.Function.Patterns,
+			Body:       method.Function.Body,
+			Env:        env,
+			Generator:  method.Function.Generator,
+			Doc:        method.Function.Doc,
+		}
+	}
+	if out := env.Set(structType.Name, structType); isError(out) {
+		return out
+	}
+	return object.NullValue
+}
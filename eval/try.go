@@ -0,0 +1,67 @@
+package eval
+
+import (
+	"mitchlang/ast"
+	"mitchlang/object"
+)
+
+// evalThrow raises value. Strings raise an Exception with the string as
+// its message and error values are raised again. An error value is raised
+// as a copy so the calls it unwinds through aren't added to the trace of
+// the value.
+func evalThrow(value object.Object) object.Object {
+	switch v := value.(type) {
+	case *object.ErrorValue:
+		err := *v.Err
+		err.Trace = make([]string, len(v.Err.Trace))
+		copy(err.Trace, v.Err.Trace)
+		return &err
+	case *object.String:
+		return &object.Error{ErrorType: object.ErrorTypeException, Message: v.Value}
+	default:
+		return object.NewTypeError("can only throw str or error, got %s", value.Type())
+	}
+}
+
+func evalTryStatement(n *ast.TryStatement, env *object.Env) object.Object {
+	result := Eval(n.Block, env)
+	if err, ok := result.(*object.Error); ok {
+		if clause := findCatchClause(n.Catches, err); clause != nil {
+			result = evalCatchClause(clause, err, env)
+		}
+	}
+	if n.Finally != nil {
+		// an error or return in the finally block replaces the result of
+		// the try and catch blocks
+		out := Eval(n.Finally, env)
+		switch out.(type) {
+		case *object.Error:
+			return out
+		case *object.ReturnValue:
+			return out
+		}
+	}
+	return result
+}
+
+func findCatchClause(clauses []*ast.CatchClause, err *object.Error) *ast.CatchClause {
+	for _, clause := range clauses {
+		if clause.ErrorType == nil || clause.ErrorType.Value == object.ErrorTypeException {
+			return clause
+		}
+		if object.ErrorType(clause.ErrorType.Value) == err.Kind() {
+			return clause
+		}
+	}
+	return nil
+}
+
+// evalCatchClause runs the body of the clause in a new scope, binding
+// the caught error to the name of the clause
+func evalCatchClause(clause *ast.CatchClause, err *object.Error, env *object.Env) object.Object {
+	catchEnv := env.Push()
+	if clause.Name != nil {
+		catchEnv.Set(clause.Name.Value, &object.ErrorValue{Err: err})
+	}
+	return Eval(clause.Body, catchEnv)
+}
@@ -1,7 +1,9 @@
 package lexer
 
 import (
+	"fmt"
 	"mitchlang/token"
+	"strings"
 )
 
 type Lexer struct {
@@ -9,6 +11,8 @@ type Lexer struct {
 	position     int
 	readPosition int
 	ch           byte
+	// doc holds the doc comments read since the last token
+	doc string
 }
 
 func (l *Lexer) readChar() {
@@ -28,6 +32,15 @@ func (l *Lexer) peekChar() byte {
 	return l.input[l.readPosition]
 }
 
+// peekCharN returns the character n positions ahead of the current one
+func (l *Lexer) peekCharN(n int) byte {
+	position := l.position + n
+	if position >= len(l.input) {
+		return 0
+	}
+	return l.input[position]
+}
+
 func (l *Lexer) readWhen(pred func(byte) bool) string {
 	if l.position >= len(l.input) {
 		// EOF
@@ -41,11 +54,63 @@ func (l *Lexer) readWhen(pred func(byte) bool) string {
 	return l.input[position:l.position]
 }
 
-func (l *Lexer) readString() string {
-	s := l.readWhen(func(b byte) bool {
-		return b != '"' && b != 0
-	})
-	return s
+// readString reads a string literal up to its closing quote. Strings
+// containing ${expression} are read as an InterpolatedString.
+func (l *Lexer) readString() *token.Token {
+	start := l.position
+	text := start
+	var parts []string
+	for l.ch != '"' && l.ch != 0 {
+		if l.ch != '$' || l.peekChar() != '{' {
+			l.readChar()
+			continue
+		}
+		parts = append(parts, l.input[text:l.position])
+		l.readChar()
+		l.readChar()
+		expression := l.position
+		if !l.skipInterpolation() {
+			return token.NewFromString(token.Illegal, "unterminated string interpolation")
+		}
+		parts = append(parts, l.input[expression:l.position])
+		// skip the closing brace
+		l.readChar()
+		text = l.position
+	}
+	literal := l.input[start:l.position]
+	if parts == nil {
+		return token.NewFromString(token.String, literal)
+	}
+	parts = append(parts, l.input[text:l.position])
+	return &token.Token{Type: token.InterpolatedString, Literal: literal, Parts: parts}
+}
+
+// skipInterpolation moves to the brace that closes an embedded expression,
+// skipping nested braces and strings. It reports false if the input ends
+// first.
+func (l *Lexer) skipInterpolation() bool {
+	depth := 0
+	for l.ch != 0 {
+		switch l.ch {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return true
+			}
+			depth--
+		case '"':
+			l.readChar()
+			for l.ch != '"' && l.ch != 0 {
+				l.readChar()
+			}
+			if l.ch == 0 {
+				return false
+			}
+		}
+		l.readChar()
+	}
+	return false
 }
 
 func (l *Lexer) readIdentifier() string {
@@ -66,7 +131,54 @@ func (l *Lexer) readComment() {
 	})
 }
 
+// readDocComment reads the text of a /// comment, which is attached to
+// the next token
+func (l *Lexer) readDocComment() {
+	position := l.position
+	for l.ch != 0 && l.ch != '\n' {
+		l.readChar()
+	}
+	text := strings.TrimPrefix(l.input[position:l.position], " ")
+	if l.doc != "" {
+		l.doc += "\n"
+	}
+	l.doc += strings.TrimRight(text, " \t\r")
+}
+
+// skipBlockComment skips a /* */ comment, which may contain nested block
+// comments. It reports false if the input ends before the comment is
+// closed.
+func (l *Lexer) skipBlockComment() bool {
+	depth := 0
+	for l.ch != 0 {
+		if l.ch == '/' && l.peekChar() == '*' {
+			depth++
+			l.readChar()
+		} else if l.ch == '*' && l.peekChar() == '/' {
+			depth--
+			l.readChar()
+			if depth == 0 {
+				l.readChar()
+				return true
+			}
+		}
+		l.readChar()
+	}
+	return false
+}
+
+// NextToken returns the next token of the input. Doc comments written
+// before the token are attached to it.
 func (l *Lexer) NextToken() *token.Token {
+	tok := l.nextToken()
+	if l.doc != "" {
+		tok.Doc = l.doc
+		l.doc = ""
+	}
+	return tok
+}
+
+func (l *Lexer) nextToken() *token.Token {
 
 	_ = l.readWhitespace()
 
@@ -76,6 +188,9 @@ func (l *Lexer) NextToken() *token.Token {
 		if l.peekChar() == '=' {
 			tok = token.New(token.Eq, l.ch, l.peekChar())
 			l.readChar()
+		} else if l.peekChar() == '>' {
+			tok = token.New(token.Arrow, l.ch, l.peekChar())
+			l.readChar()
 		} else {
 			tok = token.New(token.Assign, l.ch)
 		}
@@ -93,17 +208,44 @@ func (l *Lexer) NextToken() *token.Token {
 	case '*':
 		tok = token.New(token.Asterisk, l.ch)
 	case '/':
-		if l.peekChar() == '/' {
+		switch {
+		case l.peekChar() == '/' && l.peekCharN(2) == '/' && l.peekCharN(3) != '/':
+			// skip the slashes of a /// doc comment
+			l.readChar()
+			l.readChar()
+			l.readChar()
+			l.readDocComment()
+			return l.nextToken()
+		case l.peekChar() == '/':
 			l.readChar()
 			l.readComment()
-			return l.NextToken()
-		} else {
+			return l.nextToken()
+		case l.peekChar() == '*':
+			if !l.skipBlockComment() {
+				return token.NewFromString(token.Illegal, "unterminated block comment")
+			}
+			return l.nextToken()
+		default:
 			tok = token.New(token.Slash, l.ch)
 		}
 	case '<':
 		tok = token.New(token.LT, l.ch)
 	case '>':
 		tok = token.New(token.GT, l.ch)
+	case '?':
+		switch l.peekChar() {
+		case '?':
+			tok = token.New(token.Coalesce, l.ch, l.peekChar())
+			l.readChar()
+		case '.':
+			tok = token.New(token.OptionalDot, l.ch, l.peekChar())
+			l.readChar()
+		case '[':
+			tok = token.New(token.OptionalIndex, l.ch, l.peekChar())
+			l.readChar()
+		default:
+			tok = token.New(token.Question, l.ch)
+		}
 	case ',':
 		tok = token.New(token.Comma, l.ch)
 	case ';':
@@ -123,10 +265,23 @@ func (l *Lexer) NextToken() *token.Token {
 	case ']':
 		tok = token.New(token.RBracket, l.ch)
 	case '.':
-		tok = token.New(token.Dot, l.ch)
+		if l.peekChar() == '.' && l.peekCharN(2) == '.' {
+			tok = token.New(token.Ellipsis, l.ch, l.ch, l.ch)
+			l.readChar()
+			l.readChar()
+		} else if l.peekChar() == '.' && l.peekCharN(2) == '=' {
+			tok = token.New(token.RangeEq, l.ch, l.ch, '=')
+			l.readChar()
+			l.readChar()
+		} else if l.peekChar() == '.' {
+			tok = token.New(token.Range, l.ch, l.ch)
+			l.readChar()
+		} else {
+			tok = token.New(token.Dot, l.ch)
+		}
 	case '"':
 		l.readChar()
-		tok = token.NewFromString(token.String, l.readString())
+		tok = l.readString()
 	case 0:
 		tok = token.New(token.EOF)
 	default:
@@ -136,7 +291,8 @@ func (l *Lexer) NextToken() *token.Token {
 			} else if isDigit(l.ch) {
 				tok = token.NewFromString(token.Int, l.readInt())
 			} else {
-				tok = token.New(token.Illegal, l.ch)
+				tok = token.NewFromString(token.Illegal, fmt.Sprintf("illegal character %q", l.ch))
+				l.readChar()
 			}
 			return tok
 		}
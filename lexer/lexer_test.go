@@ -16,7 +16,7 @@ let add = fn(x, y) {
 };
 
 let result = add(five, ten);
-!-/*5;
+!-/ *5;
 5 < 10 > 5
 
 if (5 < 10) {
@@ -74,7 +74,7 @@ x.y = 1
 		{token.Ident, "ten"},
 		{token.RParen, ")"},
 		{token.SemiColon, ";"},
-		// !-/*5;
+		// !-/ *5;
 		{token.Bang, "!"},
 		{token.Minus, "-"},
 		{token.Slash, "/"},
@@ -163,3 +163,156 @@ x.y = 1
 		})
 	}
 }
+
+func TestLexer_MatchTokens(t *testing.T) {
+	input := `match (x) { [a, ...rest] => a }`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Match, "match"},
+		{token.LParen, "("},
+		{token.Ident, "x"},
+		{token.RParen, ")"},
+		{token.LBrace, "{"},
+		{token.LBracket, "["},
+		{token.Ident, "a"},
+		{token.Comma, ","},
+		{token.Ellipsis, "..."},
+		{token.Ident, "rest"},
+		{token.RBracket, "]"},
+		{token.Arrow, "=>"},
+		{token.Ident, "a"},
+		{token.RBrace, "}"},
+		{token.EOF, ""},
+	}
+	lex := lexer.New(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		require.Equal(t, test.expectedType, tok.Type)
+		require.Equal(t, test.expectedLiteral, tok.Literal)
+	}
+}
+
+func TestLexer_RangeTokens(t *testing.T) {
+	input := `0..10 0..=n [...xs] x.y`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Int, "0"},
+		{token.Range, ".."},
+		{token.Int, "10"},
+		{token.Int, "0"},
+		{token.RangeEq, "..="},
+		{token.Ident, "n"},
+		{token.LBracket, "["},
+		{token.Ellipsis, "..."},
+		{token.Ident, "xs"},
+		{token.RBracket, "]"},
+		{token.Ident, "x"},
+		{token.Dot, "."},
+		{token.Ident, "y"},
+		{token.EOF, ""},
+	}
+	lex := lexer.New(input)
+
+	for _, test := range tests {
+		tok := lex.NextToken()
+		require.Equal(t, test.expectedType, tok.Type)
+		require.Equal(t, test.expectedLiteral, tok.Literal)
+	}
+}
+
+func TestLexer_ContextualKeywords(t *testing.T) {
+	lex := lexer.New(`a is not b`)
+	for _, expected := range []token.Type{token.Ident, token.Is, token.Not, token.Ident} {
+		tok := lex.NextToken()
+		require.Equal(t, token.Ident, tok.Type)
+		require.Equal(t, expected, tok.InfixType())
+	}
+}
+
+func TestLexer_InterpolatedString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected *token.Token
+	}{
+		{`"plain $ text"`, token.NewFromString(token.String, "plain $ text")},
+		{`"hi ${name}!"`, &token.Token{Type: token.InterpolatedString, Literal: "hi ${name}!", Parts: []string{"hi ", "name", "!"}}},
+		{`"${a}${b + 1}"`, &token.Token{Type: token.InterpolatedString, Literal: "${a}${b + 1}", Parts: []string{"", "a", "", "b + 1", ""}}},
+		{`"${ {"k": 1}["k"] } }"`, &token.Token{Type: token.InterpolatedString, Literal: `${ {"k": 1}["k"] } }`, Parts: []string{"", ` {"k": 1}["k"] `, " }"}}},
+		{`"a ${"}"} b"`, &token.Token{Type: token.InterpolatedString, Literal: `a ${"}"} b`, Parts: []string{"a ", `"}"`, " b"}}},
+		{`"${x"`, token.NewFromString(token.Illegal, "unterminated string interpolation")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tok := lexer.New(tt.input).NextToken()
+			require.Equal(t, tt.expected, tok)
+		})
+	}
+
+	lex := lexer.New(`"n=${n}" + 1`)
+	for _, expected := range []token.Type{token.InterpolatedString, token.Plus, token.Int, token.EOF} {
+		require.Equal(t, expected, lex.NextToken().Type)
+	}
+}
+
+func TestLexer_Comments(t *testing.T) {
+	input := `// line comment
+1 /* block */ 2
+/* nested /* block */ comment */ 3
+/*
+  spanning lines
+*/ 4 //// not a doc comment
+5 / 6
+`
+	lex := lexer.New(input)
+	for _, expected := range []string{"1", "2", "3", "4", "5", "/", "6", ""} {
+		tok := lex.NextToken()
+		require.Equal(t, expected, tok.Literal)
+		require.Empty(t, tok.Doc)
+	}
+
+	tok := lexer.New(`/* never /* closed */`).NextToken()
+	require.Equal(t, token.NewFromString(token.Illegal, "unterminated block comment"), tok)
+}
+
+func TestLexer_IllegalCharacter(t *testing.T) {
+	lex := lexer.New(`a \ b`)
+	for _, expected := range []token.Type{token.Ident, token.Illegal, token.Ident, token.EOF} {
+		tok := lex.NextToken()
+		require.Equal(t, expected, tok.Type)
+		if tok.IsType(token.Illegal) {
+			require.Equal(t, `illegal character '\\'`, tok.Literal)
+		}
+	}
+}
+
+func TestLexer_DocComments(t *testing.T) {
+	input := `/// Adds two numbers.
+///
+/// Both must be ints.
+let add = fn(a, b) { a + b };
+let plain = 1;
+///no space
+// regular comments in between are ignored
+fn`
+	lex := lexer.New(input)
+
+	tok := lex.NextToken()
+	require.Equal(t, token.Let, tok.Type)
+	require.Equal(t, "Adds two numbers.\n\nBoth must be ints.", tok.Doc)
+	for tok = lex.NextToken(); tok.Type != token.Let; tok = lex.NextToken() {
+		require.Empty(t, tok.Doc)
+	}
+	require.Empty(t, tok.Doc)
+	for tok = lex.NextToken(); tok.Type != token.Function; tok = lex.NextToken() {
+		require.Empty(t, tok.Doc)
+	}
+	require.Equal(t, "no space", tok.Doc)
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"os/user"
+	"path/filepath"
 
 	"mitchlang/eval"
 	"mitchlang/lexer"
@@ -17,27 +20,12 @@ import (
 func main() {
 	flag.Parse()
 	if flag.NArg() > 0 {
-		raw, err := os.ReadFile(flag.Arg(0))
-		if err != nil {
-			panic(err)
-		}
-		script := string(raw)
-		l := lexer.New(script)
-		p := parser.New(l)
-		env := object.NewEnv()
-		obj := eval.Eval(p.ParseProgram(), env)
-		if obj.Type() == object.TypeError {
-			_, _ = io.WriteString(os.Stderr, obj.Inspect())
-			_, _ = io.WriteString(os.Stderr, "\n")
-			os.Exit(1)
-		}
-		if len(p.Errors()) > 0 {
-			for _, err := range p.Errors() {
-				_, _ = io.WriteString(os.Stderr, err)
-			}
-			os.Exit(1)
-		}
-		os.Exit(0)
+		// an interrupt cancels the running script, stopping any tasks it
+		// spawned
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		code := runFile(ctx, flag.Arg(0), os.Stderr)
+		stop()
+		os.Exit(code)
 	}
 
 	u, err := user.Current()
@@ -48,3 +36,39 @@ func main() {
 	fmt.Printf("Feel free to type in commands\n")
 	repl.Start(os.Stdin, os.Stdout)
 }
+
+// runFile evaluates the script at path as the main module with ctx,
+// writing any error to stderr, and returns the exit code
+func runFile(ctx context.Context, path string, stderr io.Writer) int {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	script := string(raw)
+	l := lexer.New(script)
+	p := parser.New(l)
+	// modules are searched for next to the importing file and then in
+	// each directory listed in MITCHPATH
+	importer := eval.NewImporter(filepath.SplitList(os.Getenv("MITCHPATH"))...)
+	env := object.NewModule("main", path, importer).Env
+	program := p.ParseProgram()
+	if len(p.Errors()) > 0 {
+		for _, err := range p.Errors() {
+			_, _ = io.WriteString(stderr, err+"\n")
+		}
+		return 1
+	}
+	eval.DefineMacros(program, env)
+	var obj object.Object
+	if expanded, err := eval.ExpandMacros(program, env); err != nil {
+		obj = err
+	} else {
+		obj = eval.EvalContext(ctx, expanded, env)
+	}
+	if obj.Type() == object.TypeError {
+		_, _ = io.WriteString(stderr, obj.Inspect())
+		_, _ = io.WriteString(stderr, "\n")
+		return 1
+	}
+	return 0
+}
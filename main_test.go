@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunFile(t *testing.T) {
+	tests := []struct {
+		source string
+		code   int
+		stderr string
+	}{
+		{`export let answer = 42; export let double = fn(x) { return x * 2; }; double(answer)`, 0, ""},
+		{`import "helper"; helper.triple(2)`, 0, ""},
+		{`throw "boom"`, 1, "boom"},
+		{`let s = "${}"; print(s);`, 1, "must hold a single expression"},
+		{`let x = ;`, 1, "no prefix parse function"},
+	}
+
+	dir := t.TempDir()
+	helper := `export let triple = fn(x) { return x * 3; }`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "helper.mitch"), []byte(helper), 0o644))
+	for _, subtest := range tests {
+		t.Run(subtest.source, func(t *testing.T) {
+			path := filepath.Join(dir, "main.mitch")
+			require.NoError(t, os.WriteFile(path, []byte(subtest.source), 0o644))
+			var stderr strings.Builder
+			code := runFile(context.Background(), path, &stderr)
+			require.Equal(t, subtest.code, code, stderr.String())
+			require.Contains(t, stderr.String(), subtest.stderr)
+		})
+	}
+}
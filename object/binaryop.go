@@ -1,6 +1,7 @@
 package object
 
 import (
+	"context"
 	"reflect"
 )
 
@@ -14,10 +15,31 @@ type comparable interface {
 	Lt(Object) Object
 }
 
-type BinaryOpFunc func(ob1, ob2 Object) Object
+// The context variants of the operators are implemented by objects whose
+// operators call script methods, which run with the ctx of the caller
+type contextAddend interface {
+	AddContext(ctx context.Context, other Object) Object
+}
+type contextEqualer interface {
+	EqContext(ctx context.Context, other Object) Object
+}
+type contextOrdered interface {
+	LtContext(ctx context.Context, other Object) Object
+}
+
+// container is implemented by objects that support the in operator
+type container interface {
+	Contains(Object) Object
+}
+
+type contextContainer interface {
+	ContainsContext(ctx context.Context, value Object) Object
+}
+
+type BinaryOpFunc func(ctx context.Context, ob1, ob2 Object) Object
 
 func strict(opFunc BinaryOpFunc, v interface{}, op string) BinaryOpFunc {
-	return func(ob1, ob2 Object) Object {
+	return func(ctx context.Context, ob1, ob2 Object) Object {
 
 		t1 := ob1.Type()
 		t2 := ob2.Type()
@@ -29,11 +51,28 @@ func strict(opFunc BinaryOpFunc, v interface{}, op string) BinaryOpFunc {
 		if t1 != t2 {
 			return NewTypeError("type mismatch: %s %s %s", t1, op, t2)
 		}
-		return opFunc(ob1, ob2)
+		return opFunc(ctx, ob1, ob2)
 	}
 }
 
-func add(obj1, obj2 Object) Object {
+// nullable lets values of any type be compared with null, which is only
+// equal to itself, before the operands are checked by opFunc. The result
+// is negated for !=.
+func nullable(opFunc BinaryOpFunc, negate bool) BinaryOpFunc {
+	return func(ctx context.Context, ob1, ob2 Object) Object {
+		_, null1 := ob1.(*Null)
+		_, null2 := ob2.(*Null)
+		if null1 || null2 {
+			return nativeBool((null1 && null2) != negate)
+		}
+		return opFunc(ctx, ob1, ob2)
+	}
+}
+
+func add(ctx context.Context, obj1, obj2 Object) Object {
+	if ob, ok := obj1.(contextAddend); ok {
+		return ob.AddContext(ctx, obj2)
+	}
 	if _, ok := obj1.(addend); !ok {
 		// type error - obj1 does not support add
 		return nil
@@ -48,7 +87,7 @@ func add(obj1, obj2 Object) Object {
 // objects of the same type can be added.
 var Add = strict(add, (*addend)(nil), "+")
 
-func sub(obj1, obj2 Object) Object {
+func sub(_ context.Context, obj1, obj2 Object) Object {
 	term := obj1.(term)
 	if diff := term.Sub(obj2); diff != nil {
 		return diff
@@ -58,7 +97,7 @@ func sub(obj1, obj2 Object) Object {
 
 var Sub = strict(sub, (*term)(nil), "-")
 
-func mul(obj1, obj2 Object) Object {
+func mul(_ context.Context, obj1, obj2 Object) Object {
 	multiplier, ok := obj1.(multiplier)
 	if !ok {
 		return nil
@@ -71,7 +110,7 @@ func mul(obj1, obj2 Object) Object {
 
 var Mul = strict(mul, (*multiplier)(nil), "*")
 
-func div(obj1, obj2 Object) Object {
+func div(_ context.Context, obj1, obj2 Object) Object {
 	dividend, ok := obj1.(dividend)
 	if !ok {
 		return nil
@@ -84,7 +123,10 @@ func div(obj1, obj2 Object) Object {
 
 var Div = strict(div, (*dividend)(nil), "/")
 
-func eq(obj1, obj2 Object) Object {
+func eq(ctx context.Context, obj1, obj2 Object) Object {
+	if ob, ok := obj1.(contextEqualer); ok {
+		return ob.EqContext(ctx, obj2)
+	}
 	ob, ok := obj1.(comparable)
 	if !ok {
 		return nil
@@ -95,10 +137,10 @@ func eq(obj1, obj2 Object) Object {
 	return nil
 }
 
-var Eq = strict(eq, (*comparable)(nil), "==")
+var Eq = nullable(strict(eq, (*comparable)(nil), "=="), false)
 
-func notEq(obj1, obj2 Object) Object {
-	eq := Eq(obj1, obj2)
+func notEq(ctx context.Context, obj1, obj2 Object) Object {
+	eq := Eq(ctx, obj1, obj2)
 	if eq == nil {
 		return nil
 	}
@@ -108,9 +150,12 @@ func notEq(obj1, obj2 Object) Object {
 	return True
 }
 
-var NotEq = strict(notEq, (*comparable)(nil), "!=")
+var NotEq = nullable(strict(notEq, (*comparable)(nil), "!="), true)
 
-func lt(obj1, obj2 Object) Object {
+func lt(ctx context.Context, obj1, obj2 Object) Object {
+	if ob, ok := obj1.(contextOrdered); ok {
+		return ob.LtContext(ctx, obj2)
+	}
 	ob, ok := obj1.(comparable)
 	if !ok {
 		return nil
@@ -124,18 +169,118 @@ func lt(obj1, obj2 Object) Object {
 
 var Lt = strict(lt, (*comparable)(nil), "<")
 
-func ltEq(obj1, obj2 Object) Object {
-	if Lt(obj1, obj2) == True || Eq(obj1, obj2) == True {
+// ltEq is derived from Lt and Eq, so it fails with their errors when the
+// operands can't be ordered
+func ltEq(ctx context.Context, obj1, obj2 Object) Object {
+	less := Lt(ctx, obj1, obj2)
+	if isError(less) || less == True {
+		return less
+	}
+	eq := Eq(ctx, obj1, obj2)
+	if isError(eq) {
+		return eq
+	}
+	return nativeBool(eq == True)
+}
+
+func gt(ctx context.Context, obj1, obj2 Object) Object {
+	lessOrEqual := ltEq(ctx, obj1, obj2)
+	if isError(lessOrEqual) {
+		return lessOrEqual
+	}
+	return nativeBool(lessOrEqual == False)
+}
+
+var Gt = strict(gt, (*comparable)(nil), ">")
+
+// In reports whether obj1 is contained in obj2. Unlike the arithmetic
+// and comparison operators the operands may be of different types.
+func In(ctx context.Context, obj1, obj2 Object) Object {
+	if c, ok := obj2.(contextContainer); ok {
+		return c.ContainsContext(ctx, obj1)
+	}
+	c, ok := obj2.(container)
+	if !ok {
+		return NewTypeError("argument of type %s is not a container", obj2.Type())
+	}
+	return c.Contains(obj1)
+}
+
+func NotIn(ctx context.Context, obj1, obj2 Object) Object {
+	in := In(ctx, obj1, obj2)
+	if in == True {
+		return False
+	}
+	if in == False {
 		return True
 	}
-	return False
+	return in
 }
 
-func gt(obj1, obj2 Object) Object {
-	if ltEq(obj1, obj2) == False {
+// Is reports whether obj1 and obj2 are the same object. Values that are
+// equal aren't necessarily identical.
+func Is(_ context.Context, obj1, obj2 Object) Object {
+	if obj1 == obj2 {
 		return True
 	}
 	return False
 }
 
-var Gt = strict(gt, (*comparable)(nil), ">")
+func IsNot(_ context.Context, obj1, obj2 Object) Object {
+	if obj1 == obj2 {
+		return False
+	}
+	return True
+}
+
+// equal reports whether two objects of any type are equal, treating
+// objects of different types or that can't be compared as unequal. Lists
+// and maps are equal when they hold equal values, so the fields of
+// structs and enum payloads holding them are compared structurally.
+func equal(ctx context.Context, obj1, obj2 Object) bool {
+	if obj1 == obj2 {
+		return true
+	}
+	if obj1.Type() != obj2.Type() {
+		return false
+	}
+	switch o1 := obj1.(type) {
+	case *List:
+		if o2, ok := obj2.(*List); ok {
+			return listsEqual(ctx, o1, o2)
+		}
+	case *Map:
+		if o2, ok := obj2.(*Map); ok {
+			return mapsEqual(ctx, o1, o2)
+		}
+	}
+	return Eq(ctx, obj1, obj2) == True
+}
+
+func listsEqual(ctx context.Context, l1, l2 *List) bool {
+	values1, values2 := l1.Values(), l2.Values()
+	if len(values1) != len(values2) {
+		return false
+	}
+	for k := range values1 {
+		if !equal(ctx, values1[k], values2[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// mapsEqual compares the entries of two maps regardless of their order
+func mapsEqual(ctx context.Context, m1, m2 *Map) bool {
+	pairs := m1.Pairs()
+	if int64(len(pairs)) != m2.length().Value {
+		return false
+	}
+	for _, pair := range pairs {
+		value, ok := m2.Get(pair.Key)
+		if !ok || !equal(ctx, pair.Value, value) {
+			return false
+		}
+	}
+	return true
+}
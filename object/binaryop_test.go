@@ -0,0 +1,24 @@
+package object
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLtEq(t *testing.T) {
+	ctx := context.Background()
+	one, two := &Integer{Value: 1}, &Integer{Value: 2}
+	require.Equal(t, True, ltEq(ctx, one, two))
+	require.Equal(t, True, ltEq(ctx, one, one))
+	require.Equal(t, False, ltEq(ctx, two, one))
+
+	plain := &StructType{Name: "Plain", Fields: []string{"v"}}
+	p1 := plain.New(one).(*Instance)
+	p2 := plain.New(two).(*Instance)
+	for _, out := range []Object{ltEq(ctx, p1, p2), ltEq(ctx, p1, p1), Gt(ctx, p1, p2), Gt(ctx, p2, p1)} {
+		require.IsType(t, &Error{}, out)
+		require.Equal(t, "Plain does not support < operator", out.(*Error).Message)
+	}
+}
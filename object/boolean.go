@@ -9,8 +9,19 @@ var (
 
 type Boolean struct{ Value bool }
 
+// nativeBool returns the shared Boolean for b
+func nativeBool(b bool) *Boolean {
+	if b {
+		return True
+	}
+	return False
+}
+
 func (b *Boolean) Inspect() string { return strconv.FormatBool(b.Value) }
 func (b *Boolean) Type() Type      { return TypeBoolean }
+func (b *Boolean) HashKey() HashKey {
+	return HashKey{Type: TypeBoolean, Value: b.Inspect()}
+}
 func (b *Boolean) eq(o *Boolean) *Boolean {
 	if b.Value == o.Value {
 		return True
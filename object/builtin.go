@@ -1,6 +1,7 @@
 package object
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -8,8 +9,26 @@ import (
 
 type BuiltinFunction func(args ...Object) Object
 
+// ContextFunction is a builtin which is passed the context of the caller
+// and any keyword arguments. Builtins that block or run script code use it
+// to stop early when the context is cancelled.
+type ContextFunction func(ctx context.Context, kwargs map[string]Object, args ...Object) Object
+
 type Builtin struct {
 	Fn BuiltinFunction
+	// ContextFn is called instead of Fn when set
+	ContextFn ContextFunction
+}
+
+// Call calls the builtin with the context of the caller
+func (b *Builtin) Call(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+	if b.ContextFn != nil {
+		return b.ContextFn(ctx, kwargs, args...)
+	}
+	if err := checkKeywords(kwargs); err != nil {
+		return err
+	}
+	return b.Fn(args...)
 }
 
 func (b *Builtin) Type() Type      { return TypeBuiltin }
@@ -19,11 +38,36 @@ var _ Object = &Builtin{}
 
 type iterable interface{ Len() Object }
 
-func BuiltinLen(args ...Object) Object {
+// contextIterable is implemented by objects whose length is computed by a
+// script method, which runs with the ctx of the caller
+type contextIterable interface {
+	LenContext(ctx context.Context) Object
+}
+
+type indexAssignable interface {
+	SetIndex(index Object, value Object) Object
+}
+
+// SetIndex stores value at index in obj
+func SetIndex(obj Object, index Object, value Object) Object {
+	container, ok := obj.(indexAssignable)
+	if !ok {
+		return NewTypeError("object does not support item assignment: %s", obj.Type())
+	}
+	return container.SetIndex(index, value)
+}
+
+func BuiltinLen(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+	if err := checkKeywords(kwargs); err != nil {
+		return err
+	}
 	if len(args) > 1 {
 		return NewTypeError("expected 1 position argument but received %d", len(args))
 	}
 	obj := args[0]
+	if it, ok := obj.(contextIterable); ok {
+		return it.LenContext(ctx)
+	}
 	it, ok := obj.(iterable)
 	if !ok {
 		return NewTypeError("object is not iterable: %s", obj.Type())
@@ -31,12 +75,15 @@ func BuiltinLen(args ...Object) Object {
 	return it.Len()
 }
 
-func BuiltinAdd(args ...Object) Object {
+func BuiltinAdd(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+	if err := checkKeywords(kwargs); err != nil {
+		return err
+	}
 	if len(args) != 2 {
 		return NewTypeError("expected 2 position arguments but received %d", len(args))
 	}
 	one, two := args[0], args[1]
-	rv := Add(one, two)
+	rv := Add(ctx, one, two)
 	if rv == nil {
 		return nil
 	}
@@ -64,22 +111,66 @@ func BuiltinExit(args ...Object) Object {
 	return NullValue
 }
 
-func BuiltinList(args ...Object) Object {
+// BuiltinList collects the values of a sequence into a list. Receiving
+// from a channel stops early when ctx is cancelled.
+func BuiltinList(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+	if err := checkKeywords(kwargs); err != nil {
+		return err
+	}
 	if len(args) > 1 {
 		return NewTypeError("expected 1 positional argument but received %d", len(args))
 	}
-	if _, ok := args[0].(interface{ List() Object }); !ok {
+	if it, ok := args[0].(interface{ List() Object }); ok {
+		return it.List()
+	}
+	// collect the values of sequences that can only be iterated
+	it, err := Iter(ctx, args[0])
+	if err != nil {
 		return NewTypeError("object %s is not iterable", args[0].Type())
 	}
-	it := args[0].(interface{ List() Object })
-	return it.List()
+	values := []Object{}
+	for {
+		value, ok := it.Next()
+		if !ok {
+			return &List{elements: values}
+		}
+		if isError(value) {
+			return value
+		}
+		values = append(values, value)
+	}
 }
 
-func builtinPrint(args ...Object) Object {
+// BuiltinHelp returns the doc comments of a function or macro, or null
+// when it has none
+func BuiltinHelp(args ...Object) Object {
+	if len(args) != 1 {
+		return NewTypeError("expected 1 positional argument but received %d", len(args))
+	}
+	doc := ""
+	switch obj := args[0].(type) {
+	case *Function:
+		doc = obj.Doc
+	case *Macro:
+		doc = obj.Doc
+	}
+	if doc == "" {
+		return NullValue
+	}
+	return &String{Value: doc}
+}
+
+func builtinPrint(ctx context.Context, args ...Object) Object {
 	if len(args) > 1 {
 		return NewTypeError("expected 1 positional arguments but received %d", len(args))
 	}
-	if _, ok := args[0].(fmt.Stringer); ok {
+	if s, ok := args[0].(stringer); ok {
+		out := s.StrContext(ctx)
+		if isError(out) {
+			return out
+		}
+		_, _ = os.Stdout.WriteString(out.(*String).Value)
+	} else if _, ok := args[0].(fmt.Stringer); ok {
 		stringer := args[0].(fmt.Stringer)
 		_, _ = os.Stdout.WriteString(stringer.String())
 	} else {
@@ -89,8 +180,11 @@ func builtinPrint(args ...Object) Object {
 	return NullValue
 }
 
-func BuiltinPrintln(args ...Object) Object {
-	obj := builtinPrint(args...)
+func BuiltinPrintln(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+	if err := checkKeywords(kwargs); err != nil {
+		return err
+	}
+	obj := builtinPrint(ctx, args...)
 	if obj != NullValue {
 		return obj
 	}
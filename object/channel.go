@@ -0,0 +1,147 @@
+package object
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const TypeChannel Type = "Channel"
+
+// Channel passes values between tasks. Unbuffered channels block senders
+// until a receiver is ready.
+type Channel struct {
+	ch     chan Object
+	mu     sync.Mutex
+	closed bool
+}
+
+func NewChannel(size int) *Channel {
+	return &Channel{ch: make(chan Object, size)}
+}
+
+func (c *Channel) Type() Type      { return TypeChannel }
+func (c *Channel) Inspect() string { return "<channel>" }
+
+// Chan returns the underlying Go channel
+func (c *Channel) Chan() chan Object { return c.ch }
+
+// Send blocks until value is received or buffered, or ctx is cancelled
+func (c *Channel) Send(ctx context.Context, value Object) (out Object) {
+	defer func() {
+		// the channel may be closed while the send is blocked
+		if recover() != nil {
+			out = newClosedChannelError("send on")
+		}
+	}()
+	select {
+	case c.ch <- value:
+		return NullValue
+	case <-ctx.Done():
+		return NewCancelledError(ctx.Err())
+	}
+}
+
+// Recv blocks until a value is sent or ctx is cancelled. Once the channel
+// is closed and drained it returns null.
+func (c *Channel) Recv(ctx context.Context) Object {
+	select {
+	case value, ok := <-c.ch:
+		if !ok {
+			return NullValue
+		}
+		return value
+	case <-ctx.Done():
+		return NewCancelledError(ctx.Err())
+	}
+}
+
+func (c *Channel) Close() Object {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return newClosedChannelError("close of")
+	}
+	c.closed = true
+	close(c.ch)
+	return NullValue
+}
+
+// IterContext receives values until the channel is closed. Once ctx is
+// cancelled the iterator produces a Cancelled error as its last value.
+func (c *Channel) IterContext(ctx context.Context) Iterator {
+	return &channelIterator{c: c, ctx: ctx}
+}
+
+type channelIterator struct {
+	c    *Channel
+	ctx  context.Context
+	done bool
+}
+
+func (it *channelIterator) Next() (Object, bool) {
+	if it.done {
+		return nil, false
+	}
+	select {
+	case value, ok := <-it.c.ch:
+		it.done = !ok
+		return value, ok
+	case <-it.ctx.Done():
+		it.done = true
+		return NewCancelledError(it.ctx.Err()), true
+	}
+}
+
+func newClosedChannelError(op string) *Error {
+	return &Error{ErrorType: ErrorTypeValueError, Message: op + " closed channel"}
+}
+
+// maxChannelSize is the most values a channel can hold
+const maxChannelSize = 1 << 20
+
+// BuiltinChannel makes a channel holding up to the given number of
+// values, or an unbuffered channel when no size is given
+func BuiltinChannel(args ...Object) Object {
+	if err := checkArgs("channel", args, 0, 1); err != nil {
+		return err
+	}
+	size := int64(0)
+	if len(args) == 1 {
+		var err *Error
+		if size, err = integerArg("channel", args, 0); err != nil {
+			return err
+		}
+		if size < 0 {
+			return &Error{ErrorType: ErrorTypeValueError, Message: "channel size must not be negative"}
+		}
+		if size > maxChannelSize {
+			return &Error{
+				ErrorType: ErrorTypeValueError,
+				Message:   fmt.Sprintf("channel size must not be more than %d", maxChannelSize),
+			}
+		}
+	}
+	return NewChannel(int(size))
+}
+
+var channelMethods = map[string]ContextMethod{
+	"send": func(ctx context.Context, receiver Object, args ...Object) Object {
+		if err := checkArgs("send", args, 1, 1); err != nil {
+			return err
+		}
+		return receiver.(*Channel).Send(ctx, args[0])
+	},
+	"recv": func(ctx context.Context, receiver Object, args ...Object) Object {
+		if err := checkArgs("recv", args, 0, 0); err != nil {
+			return err
+		}
+		return receiver.(*Channel).Recv(ctx)
+	},
+	"close": func(ctx context.Context, receiver Object, args ...Object) Object {
+		if err := checkArgs("close", args, 0, 0); err != nil {
+			return err
+		}
+		return receiver.(*Channel).Close()
+	},
+}
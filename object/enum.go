@@ -0,0 +1,156 @@
+package object
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	TypeEnum    Type = "enum"
+	TypeVariant Type = "variant"
+)
+
+// EnumType is a user defined tagged union declared with
+//
+//	enum Shape { Circle(r), Rect(w, h), Empty }
+type EnumType struct {
+	Name     string
+	Variants []*Variant
+}
+
+func (e *EnumType) Type() Type      { return TypeEnum }
+func (e *EnumType) Inspect() string { return "enum " + e.Name }
+
+// AddVariant declares a variant of the enum. Fields is nil for variants
+// that carry no payload.
+func (e *EnumType) AddVariant(name string, fields []string) *Variant {
+	variant := &Variant{Enum: e, Name: name, Fields: fields}
+	if fields == nil {
+		variant.unit = &EnumValue{Variant: variant}
+	}
+	e.Variants = append(e.Variants, variant)
+	return variant
+}
+
+// Variant returns the named variant
+func (e *EnumType) Variant(name string) (*Variant, bool) {
+	for _, variant := range e.Variants {
+		if variant.Name == name {
+			return variant, true
+		}
+	}
+	return nil, false
+}
+
+// Member returns the value of a variant without a payload, or the
+// constructor of a variant with one
+func (e *EnumType) Member(name string) (Object, bool) {
+	variant, ok := e.Variant(name)
+	if !ok {
+		return nil, false
+	}
+	if variant.unit != nil {
+		return variant.unit, true
+	}
+	return variant, true
+}
+
+// Variant is a case of an EnumType. Variants with a payload are called
+// to construct a value.
+type Variant struct {
+	Enum   *EnumType
+	Name   string
+	Fields []string
+	// unit is the only value of a variant without a payload
+	unit *EnumValue
+}
+
+func (v *Variant) Type() Type { return TypeVariant }
+func (v *Variant) Inspect() string {
+	return fmt.Sprintf("%s.%s(%s)", v.Enum.Name, v.Name, strings.Join(v.Fields, ", "))
+}
+
+// New constructs a value of the variant from positional arguments, one
+// per field in declaration order
+func (v *Variant) New(args ...Object) Object {
+	if len(args) != len(v.Fields) {
+		return NewTypeError(
+			"%s.%s expected %d positional arguments but received %d",
+			v.Enum.Name, v.Name, len(v.Fields), len(args),
+		)
+	}
+	values := make([]Object, len(args))
+	copy(values, args)
+	return &EnumValue{Variant: v, Values: values}
+}
+
+// EnumValue is a value of an EnumType. Its type is the name of the enum.
+type EnumValue struct {
+	Variant *Variant
+	Values  []Object
+}
+
+func (ev *EnumValue) Type() Type { return Type(ev.Variant.Enum.Name) }
+
+func (ev *EnumValue) Inspect() string {
+	out := new(bytes.Buffer)
+	out.WriteString(ev.Variant.Enum.Name)
+	out.WriteString(".")
+	out.WriteString(ev.Variant.Name)
+	if ev.Variant.unit == nil {
+		values := make([]string, 0, len(ev.Values))
+		for _, value := range ev.Values {
+			values = append(values, value.Inspect())
+		}
+		out.WriteString("(")
+		out.WriteString(strings.Join(values, ", "))
+		out.WriteString(")")
+	}
+	return out.String()
+}
+
+// Field returns the payload value stored under name
+func (ev *EnumValue) Field(name string) (Object, bool) {
+	for k, field := range ev.Variant.Fields {
+		if field == name {
+			return ev.Values[k], true
+		}
+	}
+	return nil, false
+}
+
+func (ev *EnumValue) Eq(other Object) Object { return ev.EqContext(context.Background(), other) }
+
+// EqContext compares the variant and then the payload value by value,
+// calling the eq methods of struct payloads with ctx
+func (ev *EnumValue) EqContext(ctx context.Context, other Object) Object {
+	o, ok := other.(*EnumValue)
+	if !ok || o.Variant != ev.Variant {
+		return False
+	}
+	for k := range ev.Values {
+		if !equal(ctx, ev.Values[k], o.Values[k]) {
+			return False
+		}
+	}
+	return True
+}
+
+func (ev *EnumValue) Lt(other Object) Object {
+	return NewTypeError("%s does not support < operator", ev.Type())
+}
+
+func (ev *EnumValue) hashKey() (HashKey, bool) {
+	value := new(bytes.Buffer)
+	value.WriteString(ev.Variant.Name)
+	for _, v := range ev.Values {
+		key, ok := hashKeyOf(v)
+		if !ok {
+			return HashKey{}, false
+		}
+		fmt.Fprintf(value, "\x00%s:%s", key.Type, key.Value)
+	}
+	return HashKey{Type: ev.Type(), Value: value.String()}, true
+}
@@ -1,18 +1,99 @@
 package object
 
-import "sync"
+import (
+	"context"
+	"fmt"
+	"sync"
+)
 
+const TypeEnv Type = "Env"
+
+// Env is a scope binding names to objects. Scripts make one with
+// code.env(), or are handed one, so that code passed to eval() runs
+// in it.
 type Env struct {
-	objects sync.Map
-	outer   *Env
+	objects   *sync.Map
+	constants *sync.Map
+	outer     *Env
+	frame     *Frame
+	module    *Module
+	ctx       context.Context
 }
 
+func (env *Env) Type() Type      { return TypeEnv }
+func (env *Env) Inspect() string { return "<env>" }
+
 func (env *Env) Push() *Env {
 	e := NewEnv()
 	e.outer = env
 	return e
 }
 
+// PushFrame returns a new scope for the body of a function call made
+// with the context of the caller
+func (env *Env) PushFrame(ctx context.Context) *Env {
+	e := env.Push()
+	e.frame = &Frame{}
+	e.ctx = ctx
+	return e
+}
+
+// WithContext returns a new scope in which code runs with ctx
+func (env *Env) WithContext(ctx context.Context) *Env {
+	e := env.Push()
+	e.ctx = ctx
+	return e
+}
+
+// View returns a scope sharing the bindings of env in which code runs
+// with ctx. Unlike WithContext, names declared in the view are declared
+// in env.
+func (env *Env) View(ctx context.Context) *Env {
+	e := *env
+	e.ctx = ctx
+	return &e
+}
+
+// SetContext makes code in this scope, and in scopes pushed from it, run
+// with ctx. Unlike WithContext it doesn't push a scope, so the top level
+// scope of a module keeps its bindings and exports.
+func (env *Env) SetContext(ctx context.Context) {
+	env.ctx = ctx
+}
+
+// Context returns the context code in this scope runs with. Cancelling
+// it stops the evaluation of loops and function calls.
+func (env *Env) Context() context.Context {
+	for e := env; e != nil; e = e.outer {
+		if e.ctx != nil {
+			return e.ctx
+		}
+	}
+	return context.Background()
+}
+
+// Frame returns the frame of the innermost function call enclosing this
+// scope, or nil outside of any function
+func (env *Env) Frame() *Frame {
+	for e := env; e != nil; e = e.outer {
+		if e.frame != nil {
+			return e.frame
+		}
+	}
+	return nil
+}
+
+// Module returns the module whose top level scope encloses this scope,
+// or nil when the scope doesn't belong to a module
+func (env *Env) Module() *Module {
+	for e := env; e != nil; e = e.outer {
+		if e.module != nil {
+			return e.module
+		}
+	}
+	return nil
+}
+
 func (env *Env) Pop() *Env {
 	return env.outer
 }
@@ -28,17 +109,69 @@ func (env *Env) Get(name string) (Object, bool) {
 			return nil, false
 		}
 	}
-	obj := v.(Object)
-	return obj, true
+	obj, ok := v.(Object)
+	return obj, ok
 }
 
+// Set binds name to obj in this scope and returns obj. Names declared
+// with SetConst can't be rebound and an error is returned instead.
 func (env *Env) Set(name string, obj Object) Object {
+	if err := env.CheckDeclare(name, false); err != nil {
+		return err
+	}
 	env.objects.Store(name, obj)
 	return obj
 }
 
+// SetConst binds name to obj in this scope and prevents it from being
+// rebound. An error is returned if name is already bound in this scope.
+func (env *Env) SetConst(name string, obj Object) Object {
+	if err := env.CheckDeclare(name, true); err != nil {
+		return err
+	}
+	env.objects.Store(name, obj)
+	env.constants.Store(name, true)
+	return obj
+}
+
+// CheckDeclare returns the error that declaring name in this scope with
+// Set, or SetConst when constant is set, would fail with
+func (env *Env) CheckDeclare(name string, constant bool) *Error {
+	if constant {
+		if _, ok := env.objects.Load(name); ok {
+			return newConstError("cannot redeclare %s as constant", name)
+		}
+		return nil
+	}
+	if env.IsConst(name) {
+		return newConstError("cannot redeclare constant %s", name)
+	}
+	return nil
+}
+
+// IsConst reports whether name was declared constant in this scope
+func (env *Env) IsConst(name string) bool {
+	_, ok := env.constants.Load(name)
+	return ok
+}
+
+// Assign rebinds name in the innermost scope that defines it
+func (env *Env) Assign(name string, obj Object) Object {
+	for e := env; e != nil; e = e.outer {
+		if _, ok := e.objects.Load(name); !ok {
+			continue
+		}
+		if e.IsConst(name) {
+			return newConstError("cannot assign to constant %s", name)
+		}
+		e.objects.Store(name, obj)
+		return obj
+	}
+	return &Error{Message: fmt.Sprintf("identifier not found: %s", name)}
+}
+
 func (env *Env) Delete(name string) { env.objects.Delete(name) }
 
 func NewEnv() *Env {
-	return &Env{objects: sync.Map{}, outer: nil}
+	return &Env{objects: &sync.Map{}, constants: &sync.Map{}, outer: nil}
 }
@@ -5,23 +5,144 @@ import "fmt"
 type ErrorType string
 
 const (
-	ErrorTypeException = "Exception"
-	ErrorTypeTypeError = "TypeError"
+	ErrorTypeException   = "Exception"
+	ErrorTypeTypeError   = "TypeError"
+	ErrorTypeIndexError  = "IndexError"
+	ErrorTypeKeyError    = "KeyError"
+	ErrorTypeMatchError  = "MatchError"
+	ErrorTypeValueError  = "ValueError"
+	ErrorTypeConstError  = "ConstError"
+	ErrorTypeFrozenError = "FrozenError"
+	ErrorTypeImportError = "ImportError"
+	// ErrorTypeStopIteration is raised by next() on an exhausted generator
+	ErrorTypeStopIteration = "StopIteration"
+	ErrorTypeCancelled     = "Cancelled"
+	// ErrorTypeSyntaxError is raised when code given to eval() or parse()
+	// doesn't parse
+	ErrorTypeSyntaxError = "SyntaxError"
 )
 
+// Error is an error being raised. Evaluation stops at the first Error
+// until it is caught, at which point it becomes an ErrorValue.
 type Error struct {
 	Message   string
 	ErrorType ErrorType
+	// Trace lists the calls the error was raised through, innermost first
+	Trace []string
 }
 
 func (e *Error) Type() Type      { return TypeError }
 func (e *Error) Inspect() string { return e.Message }
 
+// Kind returns the type of the error, defaulting to Exception
+func (e *Error) Kind() ErrorType {
+	if e.ErrorType == "" {
+		return ErrorTypeException
+	}
+	return e.ErrorType
+}
+
 var _ Object = &Error{}
 
+// ErrorValue is an error that scripts hold as an ordinary value, such as
+// an error bound by a catch clause
+type ErrorValue struct {
+	Err *Error
+}
+
+func (ev *ErrorValue) Type() Type { return TypeErrorValue }
+func (ev *ErrorValue) Inspect() string {
+	return fmt.Sprintf("%s: %s", ev.Err.Kind(), ev.Err.Message)
+}
+
+// Get returns the type, message or trace field of the error
+func (ev *ErrorValue) Get(key Object) (Object, bool) {
+	name, ok := key.(*String)
+	if !ok {
+		return nil, false
+	}
+	switch name.Value {
+	case "type":
+		return &String{Value: string(ev.Err.Kind())}, true
+	case "message":
+		return &String{Value: ev.Err.Message}, true
+	case "trace":
+		trace := make([]Object, 0, len(ev.Err.Trace))
+		for _, frame := range ev.Err.Trace {
+			trace = append(trace, &String{Value: frame})
+		}
+		return &List{elements: trace}, true
+	default:
+		return nil, false
+	}
+}
+
+var _ Object = &ErrorValue{}
+
+func newConstError(message string, a ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(message, a...), ErrorType: ErrorTypeConstError}
+}
+
+// NewCancelledError is raised when the context of the running code is
+// cancelled
+func NewCancelledError(err error) *Error {
+	return &Error{ErrorType: ErrorTypeCancelled, Message: err.Error()}
+}
+
 func NewTypeError(message string, a ...interface{}) *Error {
 	if len(a) > 0 {
 		message = fmt.Sprintf(message, a...)
 	}
 	return &Error{Message: message, ErrorType: ErrorTypeTypeError}
 }
+
+// BuiltinError creates an error value from a message and an optional
+// error type
+func BuiltinError(args ...Object) Object {
+	if len(args) < 1 || len(args) > 2 {
+		return NewTypeError("expected 1 or 2 positional arguments but received %d", len(args))
+	}
+	message, ok := args[0].(*String)
+	if !ok {
+		return NewTypeError(
+			"expected positional argument 1 to be type %s but received type %s",
+			TypeString,
+			args[0].Type(),
+		)
+	}
+	err := &Error{Message: message.Value, ErrorType: ErrorTypeException}
+	if len(args) == 2 {
+		errorType, ok := args[1].(*String)
+		if !ok {
+			return NewTypeError(
+				"expected positional argument 2 to be type %s but received type %s",
+				TypeString,
+				args[1].Type(),
+			)
+		}
+		err.ErrorType = ErrorType(errorType.Value)
+	}
+	return &ErrorValue{Err: err}
+}
+
+func BuiltinIsError(args ...Object) Object {
+	if len(args) != 1 {
+		return NewTypeError("expected 1 positional argument but received %d", len(args))
+	}
+	if _, ok := args[0].(*ErrorValue); ok {
+		return True
+	}
+	return False
+}
+
+// BuiltinUnwrapOr returns its first argument, or the second argument when
+// the first is an error value
+func BuiltinUnwrapOr(args ...Object) Object {
+	if len(args) != 2 {
+		return NewTypeError("expected 2 positional arguments but received %d", len(args))
+	}
+	if _, ok := args[0].(*ErrorValue); ok {
+		return args[1]
+	}
+	return args[0]
+}
@@ -0,0 +1,43 @@
+package object
+
+// Frame holds the state of a single function call
+type Frame struct {
+	deferred []func() Object
+	// yield hands a value to the consumer of a generator and waits to be
+	// resumed. It is nil for calls that aren't generators.
+	yield func(Object) bool
+}
+
+// Yield suspends the generator running in the frame until its next value
+// is requested. It returns false when the generator has been closed and
+// the call should unwind.
+func (f *Frame) Yield(value Object) bool {
+	return f.yield(value)
+}
+
+// IsGenerator reports whether the frame belongs to a generator
+func (f *Frame) IsGenerator() bool { return f.yield != nil }
+
+// Defer schedules fn to run when the call returns
+func (f *Frame) Defer(fn func() Object) {
+	f.deferred = append(f.deferred, fn)
+}
+
+// RunDeferred runs the deferred functions in the reverse order they were
+// added and returns the result of the call. An error raised by a deferred
+// function replaces result unless result is already an error.
+func (f *Frame) RunDeferred(result Object) Object {
+	for len(f.deferred) > 0 {
+		last := len(f.deferred) - 1
+		fn := f.deferred[last]
+		f.deferred = f.deferred[:last]
+
+		out := fn()
+		if err, ok := out.(*Error); ok {
+			if _, failed := result.(*Error); !failed {
+				result = err
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,38 @@
+package object
+
+type freezable interface {
+	Freeze()
+	Frozen() bool
+}
+
+// Freeze makes obj and every container reachable from it immutable. It
+// returns obj so it can be used in place.
+func Freeze(obj Object) Object {
+	if f, ok := obj.(freezable); ok && !f.Frozen() {
+		f.Freeze()
+	}
+	return obj
+}
+
+// IsFrozen reports whether obj is a container that can't be modified.
+// Objects that have no way to be modified are never frozen.
+func IsFrozen(obj Object) bool {
+	if f, ok := obj.(freezable); ok {
+		return f.Frozen()
+	}
+	return false
+}
+
+func newFrozenError(t Type) *Error {
+	return &Error{
+		ErrorType: ErrorTypeFrozenError,
+		Message:   "cannot modify frozen " + t.String(),
+	}
+}
+
+func BuiltinFreeze(args ...Object) Object {
+	if len(args) != 1 {
+		return NewTypeError("expected 1 positional argument but received %d", len(args))
+	}
+	return Freeze(args[0])
+}
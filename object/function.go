@@ -8,8 +8,13 @@ import (
 
 type Function struct {
 	Parameters []*ast.Identifier
+	Patterns   []ast.Pattern
 	Body       *ast.BlockStatement
 	Env        *Env
+	// Generator is set for functions that yield
+	Generator bool
+	// Doc holds the doc comments of the function, shown by help()
+	Doc string
 }
 
 func (f *Function) Type() Type { return TypeFunction }
@@ -33,3 +38,18 @@ func (f *Function) Inspect() string {
 
 // Verify implements Object interface
 var _ Object = &Function{}
+
+// Bind returns a copy of the function which has self set to receiver when
+// it is called
+func (f *Function) Bind(receiver Object) *Function {
+	env := f.Env.Push()
+	env.Set("self", receiver)
+	return &Function{
+		Parameters: f.Parameters,
+		Patterns:   f.Patterns,
+		Body:       f.Body,
+		Env:        env,
+		Generator:  f.Generator,
+		Doc:        f.Doc,
+	}
+}
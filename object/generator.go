@@ -0,0 +1,154 @@
+package object
+
+import (
+	"runtime"
+	"sync"
+)
+
+const TypeGenerator Type = "Generator"
+
+type generatorMessage struct {
+	value Object
+	done  bool
+}
+
+// Generator produces the values yielded by a call to a generator
+// function. The body of the function runs on its own goroutine, which only
+// runs while the consumer waits for the next value.
+type Generator struct {
+	mu     sync.Mutex
+	start  func()
+	resume chan struct{}
+	values chan generatorMessage
+	// finished is closed once the body has returned
+	finished chan struct{}
+	started  bool
+	// running is set while the body runs, so a generator that asks for
+	// its own next value raises an error instead of waiting on itself
+	running bool
+	done    bool
+}
+
+// NewGenerator returns a generator that runs body in frame the first time
+// a value is requested. Values passed to frame.Yield are produced by the
+// generator. Body returns an Error to raise it from the consumer.
+func NewGenerator(frame *Frame, body func() Object) *Generator {
+	resume := make(chan struct{})
+	values := make(chan generatorMessage)
+	finished := make(chan struct{})
+	g := &Generator{resume: resume, values: values, finished: finished}
+
+	// the goroutine must not refer to g so an abandoned generator can be
+	// collected, which closes resume and lets the goroutine finish
+	g.start = func() {
+		go func() {
+			defer close(finished)
+			if _, ok := <-resume; !ok {
+				return
+			}
+			closed := false
+			frame.yield = func(value Object) bool {
+				values <- generatorMessage{value: value}
+				_, ok := <-resume
+				closed = !ok
+				return ok
+			}
+			result := body()
+			if !closed {
+				values <- generatorMessage{value: result, done: true}
+			}
+		}()
+	}
+	runtime.SetFinalizer(g, func(g *Generator) { g.stop() })
+	return g
+}
+
+func (g *Generator) Type() Type      { return TypeGenerator }
+func (g *Generator) Inspect() string { return "<generator>" }
+
+// Next resumes the generator until it yields its next value. An error
+// raised by the generator is returned as its last value.
+func (g *Generator) Next() (Object, bool) {
+	g.mu.Lock()
+	if g.done {
+		g.mu.Unlock()
+		return nil, false
+	}
+	if g.running {
+		g.mu.Unlock()
+		return &Error{ErrorType: ErrorTypeValueError, Message: "generator already executing"}, true
+	}
+	if !g.started {
+		g.started = true
+		g.start()
+	}
+	g.running = true
+	g.mu.Unlock()
+
+	g.resume <- struct{}{}
+	msg := <-g.values
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.running = false
+	// a generator stopped while it ran is closed now that it is suspended
+	stopped := g.done
+	if msg.done || stopped {
+		g.done = true
+		close(g.resume)
+	}
+	if !msg.done {
+		return msg.value, true
+	}
+	if err, ok := msg.value.(*Error); ok {
+		return err, true
+	}
+	return nil, false
+}
+
+// Close stops the generator, waiting for its body to unwind if it is
+// suspended
+func (g *Generator) Close() {
+	if g.stop() {
+		<-g.finished
+	}
+}
+
+// stop stops the generator without waiting and reports whether its body
+// is suspended. A generator stopped while its body runs is closed by the
+// call to Next that is running it.
+func (g *Generator) stop() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.done {
+		return false
+	}
+	g.done = true
+	if g.running {
+		return false
+	}
+	close(g.resume)
+	return g.started
+}
+
+func (g *Generator) Iter() Iterator { return g }
+
+// BuiltinNext returns the next value of a generator. When the generator
+// is exhausted the default is returned if one is given.
+func BuiltinNext(args ...Object) Object {
+	if len(args) < 1 || len(args) > 2 {
+		return NewTypeError("expected 1 or 2 positional arguments but received %d", len(args))
+	}
+	it, ok := args[0].(Iterator)
+	if !ok {
+		return NewTypeError("object is not an iterator: %s", args[0].Type())
+	}
+	value, ok := it.Next()
+	if ok {
+		return value
+	}
+	if len(args) == 2 {
+		return args[1]
+	}
+	return &Error{ErrorType: ErrorTypeStopIteration, Message: "generator is exhausted"}
+}
@@ -6,6 +6,9 @@ type Integer struct{ Value int64 }
 
 func (i *Integer) Inspect() string { return fmt.Sprintf("%d", i.Value) }
 func (i *Integer) Type() Type      { return TypeInteger }
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: TypeInteger, Value: i.Inspect()}
+}
 
 func (i *Integer) add(o *Integer) *Integer {
 	return &Integer{Value: i.Value + o.Value}
@@ -0,0 +1,52 @@
+package object
+
+import "context"
+
+// Iterator produces the values of a sequence one at a time. Next returns
+// false once the sequence is exhausted.
+type Iterator interface {
+	Next() (Object, bool)
+}
+
+type sequence interface{ Iter() Iterator }
+
+// contextSequence is implemented by objects whose iterators block, such
+// as channels, and stop when ctx is cancelled
+type contextSequence interface {
+	IterContext(ctx context.Context) Iterator
+}
+
+// fallibleSequence is implemented by objects that may fail to produce an
+// Iterator, such as structs that delegate to an iter method
+type fallibleSequence interface {
+	iter(ctx context.Context) (Iterator, *Error)
+}
+
+// Iter returns an Iterator over the values of obj. Iterators that block
+// return early with a Cancelled error once ctx is cancelled.
+func Iter(ctx context.Context, obj Object) (Iterator, *Error) {
+	switch seq := obj.(type) {
+	case sequence:
+		return seq.Iter(), nil
+	case contextSequence:
+		return seq.IterContext(ctx), nil
+	case fallibleSequence:
+		return seq.iter(ctx)
+	default:
+		return nil, NewTypeError("object is not iterable: %s", obj.Type())
+	}
+}
+
+type sliceIterator struct {
+	values []Object
+	pos    int
+}
+
+func (it *sliceIterator) Next() (Object, bool) {
+	if it.pos >= len(it.values) {
+		return nil, false
+	}
+	value := it.values[it.pos]
+	it.pos++
+	return value, true
+}
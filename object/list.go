@@ -2,11 +2,28 @@ package object
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 )
 
+// List is a sequence of values. Its methods are safe to use from several
+// tasks at once.
 type List struct {
-	Values []Object
+	mu       sync.RWMutex
+	elements []Object
+	frozen   bool
+}
+
+// Values returns a copy of the values of the list
+func (l *List) Values() []Object {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	values := make([]Object, len(l.elements))
+	copy(values, l.elements)
+	return values
 }
 
 func (l *List) Type() Type {
@@ -14,10 +31,11 @@ func (l *List) Type() Type {
 }
 
 func (l *List) Inspect() string {
-	values := make([]string, 0, len(l.Values))
+	items := l.Values()
+	values := make([]string, 0, len(items))
 
-	for k := range l.Values {
-		values = append(values, l.Values[k].Inspect())
+	for k := range items {
+		values = append(values, items[k].Inspect())
 	}
 	out := new(bytes.Buffer)
 	out.WriteString("[")
@@ -27,11 +45,236 @@ func (l *List) Inspect() string {
 }
 
 func (l *List) length() *Integer {
-	return &Integer{Value: int64(len(l.Values))}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return &Integer{Value: int64(len(l.elements))}
 }
 
 func (l *List) Len() Object {
 	return l.length()
 }
 
+func (l *List) sliceLen() int { return int(l.length().Value) }
+
+func (l *List) slice(indices []int) Object {
+	items := l.Values()
+	values := make([]Object, 0, len(indices))
+	for _, k := range indices {
+		if k < len(items) {
+			values = append(values, items[k])
+		}
+	}
+	return &List{elements: values}
+}
+
+// At returns the value at index k, or an IndexError when the list has
+// no such index
+func (l *List) At(k int) Object {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if k < 0 || k >= len(l.elements) {
+		return &Error{ErrorType: ErrorTypeIndexError, Message: "list index out of range"}
+	}
+	return l.elements[k]
+}
+
+func (l *List) Contains(value Object) Object {
+	return l.ContainsContext(context.Background(), value)
+}
+
+func (l *List) ContainsContext(ctx context.Context, value Object) Object {
+	for _, item := range l.Values() {
+		if equal(ctx, item, value) {
+			return True
+		}
+	}
+	return False
+}
+
+func (l *List) Iter() Iterator {
+	return &sliceIterator{values: l.Values()}
+}
+
+func (l *List) Freeze() {
+	l.mu.Lock()
+	l.frozen = true
+	l.mu.Unlock()
+	for _, value := range l.Values() {
+		Freeze(value)
+	}
+}
+
+func (l *List) Frozen() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.frozen
+}
+
+// SetIndex replaces the value at index, counting from the end of the
+// list when index is negative
+func (l *List) SetIndex(index Object, value Object) Object {
+	integer, ok := index.(*Integer)
+	if !ok {
+		return NewTypeError("expected integer, got %s", index.Type())
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.frozen {
+		return newFrozenError(l.Type())
+	}
+	k := int(integer.Value)
+	if k < 0 {
+		k = len(l.elements) + k
+	}
+	if k < 0 || k >= len(l.elements) {
+		return &Error{ErrorType: ErrorTypeIndexError, Message: "list assignment index out of range"}
+	}
+	l.elements[k] = value
+	return value
+}
+
+// NewList returns a list holding values
+func NewList(values ...Object) *List {
+	return &List{elements: values}
+}
+
 var _ Object = &List{}
+
+var listMethods = map[string]Method{
+	"push": func(receiver Object, args ...Object) Object {
+		l := receiver.(*List)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.frozen {
+			return newFrozenError(l.Type())
+		}
+		l.elements = append(l.elements, args...)
+		return NullValue
+	},
+	// pop removes and returns the last value, or the value at the given
+	// index
+	"pop": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("pop", args, 0, 1); err != nil {
+			return err
+		}
+		l := receiver.(*List)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.frozen {
+			return newFrozenError(l.Type())
+		}
+		if len(l.elements) == 0 {
+			return &Error{ErrorType: ErrorTypeIndexError, Message: "pop from empty list"}
+		}
+		k := len(l.elements) - 1
+		if len(args) == 1 {
+			integer, ok := args[0].(*Integer)
+			if !ok {
+				return NewTypeError("expected integer, got %s", args[0].Type())
+			}
+			k = int(integer.Value)
+			if k < 0 {
+				k = len(l.elements) + k
+			}
+			if k < 0 || k >= len(l.elements) {
+				return &Error{ErrorType: ErrorTypeIndexError, Message: "pop index out of range"}
+			}
+		}
+		value := l.elements[k]
+		l.elements = append(l.elements[:k], l.elements[k+1:]...)
+		return value
+	},
+	"reverse": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("reverse", args, 0, 0); err != nil {
+			return err
+		}
+		l := receiver.(*List)
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.frozen {
+			return newFrozenError(l.Type())
+		}
+		for i, j := 0, len(l.elements)-1; i < j; i, j = i+1, j-1 {
+			l.elements[i], l.elements[j] = l.elements[j], l.elements[i]
+		}
+		return NullValue
+	},
+}
+
+// listContextMethods compare the values of the list, which may call
+// methods of struct values with ctx
+var listContextMethods = map[string]ContextMethod{
+	"index": func(ctx context.Context, receiver Object, args ...Object) Object {
+		if err := checkArgs("index", args, 1, 1); err != nil {
+			return err
+		}
+		for k, value := range receiver.(*List).Values() {
+			if equal(ctx, value, args[0]) {
+				return &Integer{Value: int64(k)}
+			}
+		}
+		return &Error{
+			ErrorType: ErrorTypeValueError,
+			Message:   fmt.Sprintf("%s is not in list", args[0].Inspect()),
+		}
+	},
+	// sort orders the list in place using the < operator
+	"sort": func(ctx context.Context, receiver Object, args ...Object) Object {
+		if err := checkArgs("sort", args, 0, 0); err != nil {
+			return err
+		}
+		l := receiver.(*List)
+		if l.Frozen() {
+			return newFrozenError(l.Type())
+		}
+		// the values are sorted outside of the lock as < may call
+		// methods of the values. The sort starts over when another task
+		// changed the list in the meantime.
+		for {
+			values := l.Values()
+			sorted := make([]Object, len(values))
+			copy(sorted, values)
+			var err Object
+			sort.SliceStable(sorted, func(i, j int) bool {
+				if err != nil {
+					return false
+				}
+				less := Lt(ctx, sorted[i], sorted[j])
+				if isError(less) {
+					err = less
+					return false
+				}
+				return less == True
+			})
+			if err != nil {
+				return err
+			}
+			l.mu.Lock()
+			if l.frozen {
+				l.mu.Unlock()
+				return newFrozenError(l.Type())
+			}
+			if sameValues(l.elements, values) {
+				l.elements = sorted
+				l.mu.Unlock()
+				break
+			}
+			l.mu.Unlock()
+		}
+		return NullValue
+	},
+}
+
+// sameValues reports whether both slices hold the same objects in the
+// same order
+func sameValues(values1, values2 []Object) bool {
+	if len(values1) != len(values2) {
+		return false
+	}
+	for k := range values1 {
+		if values1[k] != values2[k] {
+			return false
+		}
+	}
+	return true
+}
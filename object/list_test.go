@@ -1,12 +1,13 @@
 package object
 
 import (
+	"context"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
 
 func TestList_Inspect(t *testing.T) {
-	list := &List{Values: []Object{
+	list := &List{elements: []Object{
 		&Integer{Value: 1},
 		&Integer{Value: 2},
 		&Integer{Value: 3},
@@ -19,7 +20,7 @@ func TestList_Inspect(t *testing.T) {
 }
 
 func TestList_Len(t *testing.T) {
-	list := &List{Values: []Object{
+	list := &List{elements: []Object{
 		&Integer{Value: 1},
 		&Integer{Value: 2},
 		&Integer{Value: 3},
@@ -29,3 +30,33 @@ func TestList_Len(t *testing.T) {
 	}}
 	require.Equal(t, &Integer{Value: 6}, list.Len())
 }
+
+func TestList_Freeze(t *testing.T) {
+	inner := &List{elements: []Object{&Integer{Value: 1}}}
+	list := &List{elements: []Object{inner}}
+
+	require.Equal(t, list, Freeze(list))
+	require.True(t, IsFrozen(list))
+	require.True(t, IsFrozen(inner))
+	require.IsType(t, &Error{}, inner.SetIndex(&Integer{Value: 0}, &Integer{Value: 2}))
+	require.False(t, IsFrozen(&Integer{Value: 1}))
+}
+
+func TestEqual_Structural(t *testing.T) {
+	list := func(values ...Object) *List { return &List{elements: values} }
+	ctx := context.Background()
+	one, two := &Integer{Value: 1}, &Integer{Value: 2}
+
+	require.True(t, equal(ctx, list(one, list(two)), list(one, list(two))))
+	require.False(t, equal(ctx, list(one, list(two)), list(one, list(one))))
+	require.False(t, equal(ctx, list(one), list(one, two)))
+
+	m1, m2 := NewMap(), NewMap()
+	m1.Set(&String{Value: "a"}, list(one))
+	m1.Set(&String{Value: "b"}, two)
+	m2.Set(&String{Value: "b"}, two)
+	m2.Set(&String{Value: "a"}, list(one))
+	require.True(t, equal(ctx, m1, m2))
+	m2.Set(&String{Value: "a"}, list(two))
+	require.False(t, equal(ctx, m1, m2))
+}
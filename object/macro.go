@@ -0,0 +1,50 @@
+package object
+
+import (
+	"bytes"
+	"mitchlang/ast"
+	"strings"
+)
+
+const (
+	TypeQuote Type = "Quote"
+	TypeMacro Type = "Macro"
+)
+
+// Quote is unevaluated code, produced by quote() and returned by macros
+type Quote struct {
+	Node ast.Node
+}
+
+func (q *Quote) Type() Type      { return TypeQuote }
+func (q *Quote) Inspect() string { return "quote(" + q.Node.String() + ")" }
+
+// Macro is called during macro expansion with the AST of its arguments as
+// quotes and returns the quote that replaces the call
+type Macro struct {
+	Name       string
+	Parameters []*ast.Identifier
+	Body       *ast.BlockStatement
+	Env        *Env
+	// Doc holds the doc comments of the macro, shown by help()
+	Doc string
+}
+
+func (m *Macro) Type() Type { return TypeMacro }
+
+func (m *Macro) Inspect() string {
+	out := new(bytes.Buffer)
+
+	params := make([]string, 0, len(m.Parameters))
+	for _, p := range m.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString("macro")
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") {\n")
+	out.WriteString(m.Body.String())
+	out.WriteString("\n}")
+	return out.String()
+}
@@ -0,0 +1,224 @@
+package object
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// HashKey identifies an object used as a map key. Objects that compare
+// equal produce the same HashKey.
+type HashKey struct {
+	Type  Type
+	Value string
+}
+
+type hashable interface{ HashKey() HashKey }
+
+// compositeHashable is implemented by objects that are only hashable when
+// the objects they hold are
+type compositeHashable interface {
+	hashKey() (HashKey, bool)
+}
+
+func hashKeyOf(obj Object) (HashKey, bool) {
+	switch h := obj.(type) {
+	case hashable:
+		return h.HashKey(), true
+	case compositeHashable:
+		return h.hashKey()
+	default:
+		return HashKey{}, false
+	}
+}
+
+type MapPair struct {
+	Key   Object
+	Value Object
+}
+
+// Map is safe to use from several tasks at once
+type Map struct {
+	mu    sync.RWMutex
+	pairs map[HashKey]MapPair
+	// order holds the keys in insertion order
+	order  []HashKey
+	frozen bool
+}
+
+func (m *Map) Type() Type { return TypeMap }
+
+func (m *Map) Inspect() string {
+	pairs := []string{}
+	for _, pair := range m.Pairs() {
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+	out := new(bytes.Buffer)
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// Get returns the value stored under key
+func (m *Map) Get(key Object) (Object, bool) {
+	hashKey, ok := hashKeyOf(key)
+	if !ok {
+		return nil, false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pair, ok := m.pairs[hashKey]
+	if !ok {
+		return nil, false
+	}
+	return pair.Value, true
+}
+
+// Set stores value under key and returns the value, or an error if
+// the key can't be used as a map key
+func (m *Map) Set(key Object, value Object) Object {
+	hashKey, ok := hashKeyOf(key)
+	if !ok {
+		return NewTypeError("unhashable type: %s", key.Type())
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.frozen {
+		return newFrozenError(m.Type())
+	}
+	if _, ok := m.pairs[hashKey]; !ok {
+		m.order = append(m.order, hashKey)
+	}
+	m.pairs[hashKey] = MapPair{Key: key, Value: value}
+	return value
+}
+
+// Pairs returns the entries of the map in insertion order
+func (m *Map) Pairs() []MapPair {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pairs := make([]MapPair, 0, len(m.order))
+	for _, key := range m.order {
+		pairs = append(pairs, m.pairs[key])
+	}
+	return pairs
+}
+
+func (m *Map) length() *Integer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return &Integer{Value: int64(len(m.order))}
+}
+
+func (m *Map) Len() Object { return m.length() }
+
+func (m *Map) list() *List {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	values := make([]Object, 0, len(m.order))
+	for _, key := range m.order {
+		values = append(values, m.pairs[key].Key)
+	}
+	return &List{elements: values}
+}
+
+func (m *Map) List() Object { return m.list() }
+
+// Contains reports whether key is one of the keys of the map
+func (m *Map) Contains(key Object) Object {
+	if _, ok := m.Get(key); ok {
+		return True
+	}
+	return False
+}
+
+func (m *Map) Iter() Iterator { return &sliceIterator{values: m.list().Values()} }
+
+func (m *Map) SetIndex(index Object, value Object) Object { return m.Set(index, value) }
+
+func (m *Map) Freeze() {
+	m.mu.Lock()
+	m.frozen = true
+	m.mu.Unlock()
+	for _, pair := range m.Pairs() {
+		Freeze(pair.Value)
+	}
+}
+
+func (m *Map) Frozen() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.frozen
+}
+
+func NewMap() *Map {
+	return &Map{pairs: map[HashKey]MapPair{}, order: []HashKey{}}
+}
+
+var _ Object = &Map{}
+
+func (m *Map) items() *List {
+	items := []Object{}
+	for _, pair := range m.Pairs() {
+		items = append(items, &List{elements: []Object{pair.Key, pair.Value}})
+	}
+	return &List{elements: items}
+}
+
+// BuiltinItems returns the entries of a map as a list of [key, value]
+// lists
+func BuiltinItems(args ...Object) Object {
+	if len(args) != 1 {
+		return NewTypeError("expected 1 positional argument but received %d", len(args))
+	}
+	m, ok := args[0].(*Map)
+	if !ok {
+		return NewTypeError(
+			"expected positional argument 1 to be type %s but received type %s",
+			TypeMap,
+			args[0].Type(),
+		)
+	}
+	return m.items()
+}
+
+var mapMethods = map[string]Method{
+	// get returns the value stored under key, or the default when the key
+	// is missing
+	"get": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("get", args, 1, 2); err != nil {
+			return err
+		}
+		if value, ok := receiver.(*Map).Get(args[0]); ok {
+			return value
+		}
+		if len(args) == 2 {
+			return args[1]
+		}
+		return NullValue
+	},
+	"keys": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("keys", args, 0, 0); err != nil {
+			return err
+		}
+		return receiver.(*Map).list()
+	},
+	"values": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("values", args, 0, 0); err != nil {
+			return err
+		}
+		pairs := receiver.(*Map).Pairs()
+		values := make([]Object, 0, len(pairs))
+		for _, pair := range pairs {
+			values = append(values, pair.Value)
+		}
+		return &List{elements: values}
+	},
+	"items": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("items", args, 0, 0); err != nil {
+			return err
+		}
+		return receiver.(*Map).items()
+	},
+}
@@ -0,0 +1,31 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap_Inspect(t *testing.T) {
+	m := NewMap()
+	m.Set(&String{Value: "b"}, &Integer{Value: 1})
+	m.Set(&Integer{Value: 1}, &Boolean{Value: true})
+	m.Set(&String{Value: "b"}, &Integer{Value: 2})
+
+	require.Equal(t, `{"b": 2, 1: true}`, m.Inspect())
+	require.Equal(t, &Integer{Value: 2}, m.Len())
+}
+
+func TestMap_Get(t *testing.T) {
+	m := NewMap()
+	m.Set(&String{Value: "1"}, &Integer{Value: 1})
+
+	value, ok := m.Get(&String{Value: "1"})
+	require.True(t, ok)
+	require.Equal(t, &Integer{Value: 1}, value)
+
+	_, ok = m.Get(&Integer{Value: 1})
+	require.False(t, ok)
+
+	require.IsType(t, &Error{}, m.Set(&List{}, &Integer{Value: 1}))
+}
@@ -0,0 +1,94 @@
+package object
+
+import (
+	"context"
+	"fmt"
+)
+
+// Method is an entry in the method table of a built-in type. It is called
+// with the object the method was looked up on as the receiver.
+type Method func(receiver Object, args ...Object) Object
+
+// ContextMethod is a method that may block and returns early when ctx is
+// cancelled
+type ContextMethod func(ctx context.Context, receiver Object, args ...Object) Object
+
+var methods = map[Type]map[string]Method{
+	TypeString: stringMethods,
+	TypeList:   listMethods,
+	TypeMap:    mapMethods,
+}
+
+var contextMethods = map[Type]map[string]ContextMethod{
+	TypeList:    listContextMethods,
+	TypeChannel: channelMethods,
+}
+
+// BoundMethod returns the named method of a built-in type with obj as
+// its receiver
+func BoundMethod(obj Object, name string) (*Builtin, bool) {
+	if method, ok := methods[obj.Type()][name]; ok {
+		return &Builtin{Fn: func(args ...Object) Object { return method(obj, args...) }}, true
+	}
+	if method, ok := contextMethods[obj.Type()][name]; ok {
+		return &Builtin{ContextFn: func(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+			if err := checkKeywords(kwargs); err != nil {
+				return err
+			}
+			return method(ctx, obj, args...)
+		}}, true
+	}
+	return nil, false
+}
+
+// checkArgs returns an error unless between min and max arguments were
+// passed to the named method
+func checkArgs(name string, args []Object, min, max int) *Error {
+	if len(args) >= min && len(args) <= max {
+		return nil
+	}
+	expected := fmt.Sprint(min)
+	if max != min {
+		expected = fmt.Sprintf("%d to %d", min, max)
+	}
+	return NewTypeError("%s expected %s positional arguments but received %d", name, expected, len(args))
+}
+
+// stringArg returns the argument at position k when it is a str
+func stringArg(name string, args []Object, k int) (string, *Error) {
+	s, ok := args[k].(*String)
+	if !ok {
+		return "", NewTypeError(
+			"%s expected positional argument %d to be type %s but received type %s",
+			name, k+1, TypeString, args[k].Type(),
+		)
+	}
+	return s.Value, nil
+}
+
+// integerArg returns the argument at position k when it is an int
+func integerArg(name string, args []Object, k int) (int64, *Error) {
+	i, ok := args[k].(*Integer)
+	if !ok {
+		return 0, NewTypeError(
+			"%s expected positional argument %d to be type %s but received type %s",
+			name, k+1, TypeInteger, args[k].Type(),
+		)
+	}
+	return i.Value, nil
+}
+
+// checkKeywords returns an error if kwargs holds a name that isn't
+// allowed
+func checkKeywords(kwargs map[string]Object, allowed ...string) *Error {
+	for name := range kwargs {
+		found := false
+		for _, a := range allowed {
+			found = found || a == name
+		}
+		if !found {
+			return NewTypeError("unexpected keyword argument %s", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+)
+
+const TypeModule Type = "module"
+
+// Importer loads the module at path on behalf of the module from, which
+// is nil outside of any module
+type Importer interface {
+	Import(path string, from *Module) Object
+}
+
+// Module is the namespace created by evaluating a source file. Only the
+// names it exports can be reached from other modules.
+type Module struct {
+	Name string
+	// Path is the file the module was loaded from
+	Path     string
+	Env      *Env
+	Importer Importer
+	exports  sync.Map
+}
+
+// NewModule returns an empty module whose top level scope is Env
+func NewModule(name string, path string, importer Importer) *Module {
+	m := &Module{Name: name, Path: path, Importer: importer}
+	m.Env = NewEnv()
+	m.Env.module = m
+	return m
+}
+
+func (m *Module) Type() Type      { return TypeModule }
+func (m *Module) Inspect() string { return fmt.Sprintf("<module %s>", m.Name) }
+
+// Export makes name visible to modules that import m
+func (m *Module) Export(name string) { m.exports.Store(name, true) }
+
+// Member returns the value of an exported name
+func (m *Module) Member(name string) (Object, bool) {
+	if _, ok := m.exports.Load(name); !ok {
+		return nil, false
+	}
+	return m.Env.Get(name)
+}
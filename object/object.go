@@ -3,15 +3,18 @@ package object
 type Type string
 
 const (
-	TypeString   Type = "str"
-	TypeInteger  Type = "int"
-	TypeBoolean  Type = "bool"
-	TypeNull     Type = "NULL"
-	TypeError    Type = "ERROR"
-	TypeReturn   Type = "RETURN_VALUE"
-	TypeFunction Type = "FUNCTION"
-	TypeBuiltin  Type = "BUILTIN"
-	TypeList     Type = "List"
+	TypeString     Type = "str"
+	TypeInteger    Type = "int"
+	TypeBoolean    Type = "bool"
+	TypeNull       Type = "NULL"
+	TypeError      Type = "ERROR"
+	TypeReturn     Type = "RETURN_VALUE"
+	TypeFunction   Type = "FUNCTION"
+	TypeBuiltin    Type = "BUILTIN"
+	TypeList       Type = "List"
+	TypeMap        Type = "Map"
+	TypeErrorValue Type = "error"
+	TypeRange      Type = "Range"
 )
 
 func (t Type) String() string { return string(t) }
@@ -25,5 +28,14 @@ type Null struct{}
 
 func (n *Null) Inspect() string { return "null" }
 func (n *Null) Type() Type      { return TypeNull }
+func (n *Null) Eq(other Object) Object {
+	if _, ok := other.(*Null); ok {
+		return True
+	}
+	return False
+}
+func (n *Null) Lt(other Object) Object {
+	return NewTypeError("null does not support < operator")
+}
 
 var NullValue = &Null{}
@@ -0,0 +1,84 @@
+package object
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// BuiltinParallelMap calls a function on each value of a sequence using a
+// pool of workers and returns the results in the order of the sequence.
+// The number of workers defaults to GOMAXPROCS and can be set with the
+// workers keyword. The first error raised by a call cancels the rest and
+// is returned.
+func BuiltinParallelMap(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+	if err := checkKeywords(kwargs, "workers"); err != nil {
+		return err
+	}
+	if err := checkArgs("parallel_map", args, 2, 2); err != nil {
+		return err
+	}
+	workers := runtime.GOMAXPROCS(0)
+	if obj, ok := kwargs["workers"]; ok {
+		n, ok := obj.(*Integer)
+		if !ok {
+			return NewTypeError("parallel_map expected workers to be type %s but received type %s", TypeInteger, obj.Type())
+		}
+		if n.Value < 1 {
+			return &Error{
+				ErrorType: ErrorTypeValueError,
+				Message:   "parallel_map expected at least 1 worker",
+			}
+		}
+		workers = int(n.Value)
+	}
+	values := BuiltinList(ctx, nil, args[1])
+	if isError(values) {
+		return values
+	}
+	xs := values.(*List).Values()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([]Object, len(xs))
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first Object
+	)
+	jobs := make(chan int)
+	for w := 0; w < workers && w < len(xs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range jobs {
+				out := Call(ctx, args[0], xs[k])
+				if isError(out) {
+					once.Do(func() {
+						first = out
+						cancel()
+					})
+				}
+				results[k] = out
+			}
+		}()
+	}
+feed:
+	for k := range xs {
+		select {
+		case jobs <- k:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if first != nil {
+		return first
+	}
+	if err := ctx.Err(); err != nil {
+		return NewCancelledError(err)
+	}
+	return &List{elements: results}
+}
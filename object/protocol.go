@@ -0,0 +1,155 @@
+package object
+
+import "context"
+
+// Call applies a script function to args with ctx. It is set by the
+// evaluator so objects can dispatch operators to methods written in the
+// language.
+var Call func(ctx context.Context, fn Object, args ...Object) Object
+
+// Struct methods with these names are called by the matching operators and
+// builtins
+const (
+	protocolAdd   = "add"
+	protocolEq    = "eq"
+	protocolLt    = "lt"
+	protocolLen   = "len"
+	protocolIter  = "iter"
+	protocolStr   = "str"
+	protocolIndex = "index"
+)
+
+// callMethod calls the named method of the instance with the ctx of the
+// caller, reporting false when the struct doesn't declare it
+func (i *Instance) callMethod(ctx context.Context, name string, args ...Object) (Object, bool) {
+	method, ok := i.Method(name)
+	if !ok || Call == nil {
+		return nil, false
+	}
+	return Call(ctx, method, args...), true
+}
+
+// Add, Eq, Lt and Len let instances be used wherever the built-in types
+// are. They call methods without the ctx of a caller, so the operators
+// and builtins call the Context variants instead.
+
+func (i *Instance) Add(other Object) Object { return i.AddContext(context.Background(), other) }
+func (i *Instance) Eq(other Object) Object  { return i.EqContext(context.Background(), other) }
+func (i *Instance) Lt(other Object) Object  { return i.LtContext(context.Background(), other) }
+func (i *Instance) Len() Object             { return i.LenContext(context.Background()) }
+
+func (i *Instance) AddContext(ctx context.Context, other Object) Object {
+	if out, ok := i.callMethod(ctx, protocolAdd, other); ok {
+		return out
+	}
+	return NewTypeError("invalid operation: %s + %s", i.Type(), other.Type())
+}
+
+// EqContext calls the eq method when the struct declares one and
+// otherwise compares instances of the same struct field by field,
+// comparing list and map fields by their contents
+func (i *Instance) EqContext(ctx context.Context, other Object) Object {
+	if out, ok := i.callMethod(ctx, protocolEq, other); ok {
+		return toBoolean(protocolEq, out)
+	}
+	o, ok := other.(*Instance)
+	if !ok || o.Struct != i.Struct {
+		return False
+	}
+	values1, values2 := i.values(), o.values()
+	for k := range values1 {
+		if !equal(ctx, values1[k], values2[k]) {
+			return False
+		}
+	}
+	return True
+}
+
+func (i *Instance) LtContext(ctx context.Context, other Object) Object {
+	if out, ok := i.callMethod(ctx, protocolLt, other); ok {
+		return toBoolean(protocolLt, out)
+	}
+	return NewTypeError("%s does not support < operator", i.Type())
+}
+
+func (i *Instance) LenContext(ctx context.Context) Object {
+	out, ok := i.callMethod(ctx, protocolLen)
+	if !ok {
+		return NewTypeError("object is not iterable: %s", i.Type())
+	}
+	if _, ok := out.(*Integer); !ok && !isError(out) {
+		return NewTypeError("%s must return %s, got %s", protocolLen, TypeInteger, out.Type())
+	}
+	return out
+}
+
+// iter returns an iterator over the sequence returned by the iter method
+func (i *Instance) iter(ctx context.Context) (Iterator, *Error) {
+	out, ok := i.callMethod(ctx, protocolIter)
+	if !ok {
+		return nil, NewTypeError("object is not iterable: %s", i.Type())
+	}
+	if err, ok := out.(*Error); ok {
+		return nil, err
+	}
+	return Iter(ctx, out)
+}
+
+func (i *Instance) StrContext(ctx context.Context) Object {
+	out, ok := i.callMethod(ctx, protocolStr)
+	if !ok {
+		return &String{Value: i.Inspect()}
+	}
+	if _, ok := out.(*String); !ok && !isError(out) {
+		return NewTypeError("%s must return %s, got %s", protocolStr, TypeString, out.Type())
+	}
+	return out
+}
+
+// IndexContext calls the index method of the struct
+func (i *Instance) IndexContext(ctx context.Context, index Object) Object {
+	if out, ok := i.callMethod(ctx, protocolIndex, index); ok {
+		return out
+	}
+	return NewTypeError("%s does not support indexing", i.Type())
+}
+
+func toBoolean(method string, obj Object) Object {
+	if _, ok := obj.(*Boolean); !ok && !isError(obj) {
+		return NewTypeError("%s must return %s, got %s", method, TypeBoolean, obj.Type())
+	}
+	return obj
+}
+
+func isError(obj Object) bool {
+	_, ok := obj.(*Error)
+	return ok
+}
+
+// stringer is implemented by objects which convert themselves to str
+// in a way that may fail
+type stringer interface {
+	StrContext(ctx context.Context) Object
+}
+
+// Str converts obj to the str shown by print
+func Str(ctx context.Context, obj Object) Object {
+	switch s := obj.(type) {
+	case stringer:
+		return s.StrContext(ctx)
+	case *String:
+		return s
+	default:
+		return &String{Value: obj.Inspect()}
+	}
+}
+
+func BuiltinStr(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+	if err := checkKeywords(kwargs); err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return NewTypeError("expected 1 positional argument but received %d", len(args))
+	}
+	return Str(ctx, args[0])
+}
@@ -0,0 +1,142 @@
+package object
+
+import (
+	"fmt"
+	"math"
+)
+
+// Range is a lazy sequence of integers from Start to End advancing by
+// Step. End is only part of the range when Inclusive is set.
+type Range struct {
+	Start     int64
+	End       int64
+	Step      int64
+	Inclusive bool
+	// n is the number of integers in the range
+	n int64
+}
+
+// NewRange returns the range from start to end. When inclusive is set
+// end is part of the range. An error is returned when the range holds
+// more integers than an int can count.
+func NewRange(start, end, step int64, inclusive bool) Object {
+	if step == 0 {
+		return &Error{ErrorType: ErrorTypeValueError, Message: "range step cannot be zero"}
+	}
+	n, ok := rangeLength(start, end, step, inclusive)
+	if !ok {
+		return &Error{ErrorType: ErrorTypeValueError, Message: "range is too long"}
+	}
+	return &Range{Start: start, End: end, Step: step, Inclusive: inclusive, n: n}
+}
+
+// rangeLength counts the integers in a range using unsigned arithmetic,
+// as the distance between the ends can exceed the largest int
+func rangeLength(start, end, step int64, inclusive bool) (int64, bool) {
+	var distance, stride uint64
+	switch {
+	case step > 0 && (start < end || inclusive && start == end):
+		distance, stride = uint64(end)-uint64(start), uint64(step)
+	case step < 0 && (start > end || inclusive && start == end):
+		distance, stride = uint64(start)-uint64(end), -uint64(step)
+	default:
+		return 0, true
+	}
+	n := distance / stride
+	if inclusive || distance%stride != 0 {
+		n++
+	}
+	if n > math.MaxInt64 || n == 0 {
+		// n wraps to 0 when an inclusive range covers every int
+		return 0, false
+	}
+	return int64(n), true
+}
+
+func (r *Range) Type() Type { return TypeRange }
+
+func (r *Range) Inspect() string {
+	operator := ".."
+	if r.Inclusive {
+		operator = "..="
+	}
+	if r.Step == 1 {
+		return fmt.Sprintf("%d%s%d", r.Start, operator, r.End)
+	}
+	return fmt.Sprintf("%d%s%d step %d", r.Start, operator, r.End, r.Step)
+}
+
+func (r *Range) length() int64 { return r.n }
+
+func (r *Range) Len() Object { return &Integer{Value: r.length()} }
+
+// At returns the k'th value of the range, assuming 0 <= k < Len()
+func (r *Range) At(k int) Object {
+	return &Integer{Value: r.Start + int64(k)*r.Step}
+}
+
+// maxPrealloc caps the capacity reserved up front when listing a range
+const maxPrealloc = 1 << 16
+
+func (r *Range) list() *List {
+	capacity := r.length()
+	if capacity > maxPrealloc {
+		capacity = maxPrealloc
+	}
+	values := make([]Object, 0, capacity)
+	for k := 0; k < int(r.length()); k++ {
+		values = append(values, r.At(k))
+	}
+	return &List{elements: values}
+}
+
+func (r *Range) List() Object { return r.list() }
+
+func (r *Range) Iter() Iterator { return &rangeIterator{r: r} }
+
+// Contains reports whether value is one of the integers in the range
+func (r *Range) Contains(value Object) Object {
+	integer, ok := value.(*Integer)
+	if !ok {
+		return False
+	}
+	var distance, stride uint64
+	switch {
+	case r.Step > 0 && integer.Value >= r.Start:
+		distance, stride = uint64(integer.Value)-uint64(r.Start), uint64(r.Step)
+	case r.Step < 0 && integer.Value <= r.Start:
+		distance, stride = uint64(r.Start)-uint64(integer.Value), -uint64(r.Step)
+	default:
+		return False
+	}
+	if distance%stride != 0 || distance/stride >= uint64(r.length()) {
+		return False
+	}
+	return True
+}
+
+func (r *Range) sliceLen() int { return int(r.length()) }
+
+func (r *Range) slice(indices []int) Object {
+	values := make([]Object, 0, len(indices))
+	for _, k := range indices {
+		values = append(values, r.At(k))
+	}
+	return &List{elements: values}
+}
+
+type rangeIterator struct {
+	r *Range
+	k int64
+}
+
+func (it *rangeIterator) Next() (Object, bool) {
+	if it.k >= it.r.length() {
+		return nil, false
+	}
+	value := it.r.At(int(it.k))
+	it.k++
+	return value, true
+}
+
+var _ Object = &Range{}
@@ -0,0 +1,104 @@
+package object
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRange_Inspect(t *testing.T) {
+	tests := []struct {
+		start, end, step int64
+		inclusive        bool
+		expected         string
+	}{
+		{0, 10, 1, false, "0..10"},
+		{0, 10, 1, true, "0..=10"},
+		{0, 10, 2, false, "0..10 step 2"},
+		{10, 0, -2, true, "10..=0 step -2"},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.expected, func(t *testing.T) {
+			r := NewRange(subtest.start, subtest.end, subtest.step, subtest.inclusive)
+			require.Equal(t, subtest.expected, r.Inspect())
+		})
+	}
+}
+
+func TestRange_Contains(t *testing.T) {
+	r := NewRange(1, 10, 3, false).(*Range)
+
+	require.Equal(t, True, r.Contains(&Integer{Value: 1}))
+	require.Equal(t, True, r.Contains(&Integer{Value: 7}))
+	require.Equal(t, False, r.Contains(&Integer{Value: 10}))
+	require.Equal(t, False, r.Contains(&Integer{Value: 2}))
+	require.Equal(t, False, r.Contains(&Integer{Value: -2}))
+	require.Equal(t, False, r.Contains(&String{Value: "1"}))
+
+	r = NewRange(10, 0, -5, true).(*Range)
+	require.Equal(t, True, r.Contains(&Integer{Value: 0}))
+	require.Equal(t, False, r.Contains(&Integer{Value: 15}))
+}
+
+func TestRange_Len(t *testing.T) {
+	tests := []struct {
+		name             string
+		start, end, step int64
+		inclusive        bool
+		expected         int64
+	}{
+		{"empty", 5, 0, 1, false, 0},
+		{"single inclusive", 5, 5, 1, true, 1},
+		{"uneven step", 0, 10, 3, false, 4},
+		{"descending", 10, 0, -3, true, 4},
+		{"up to max", math.MaxInt64 - 2, math.MaxInt64, 1, true, 3},
+		{"down to min", math.MinInt64 + 2, math.MinInt64, -1, true, 3},
+		{"longest", 1, math.MaxInt64, 1, true, math.MaxInt64},
+		{"wide step", math.MinInt64, math.MaxInt64, math.MaxInt64, true, 3},
+		{"min step", math.MaxInt64, math.MinInt64, math.MinInt64, false, 2},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.name, func(t *testing.T) {
+			r := NewRange(subtest.start, subtest.end, subtest.step, subtest.inclusive)
+			require.IsType(t, &Range{}, r)
+			require.Equal(t, &Integer{Value: subtest.expected}, r.(*Range).Len())
+		})
+	}
+}
+
+func TestRange_TooLong(t *testing.T) {
+	tests := []struct {
+		name             string
+		start, end, step int64
+		inclusive        bool
+	}{
+		{"every int", math.MinInt64, math.MaxInt64, 1, true},
+		{"all but one", math.MinInt64, math.MaxInt64, 1, false},
+		{"zero to max", 0, math.MaxInt64, 1, true},
+		{"descending", math.MaxInt64, math.MinInt64, -1, false},
+	}
+
+	for _, subtest := range tests {
+		t.Run(subtest.name, func(t *testing.T) {
+			r := NewRange(subtest.start, subtest.end, subtest.step, subtest.inclusive)
+			require.IsType(t, &Error{}, r)
+			require.Equal(t, ErrorType(ErrorTypeValueError), r.(*Error).ErrorType)
+		})
+	}
+}
+
+func TestRange_Bounds(t *testing.T) {
+	r := NewRange(math.MaxInt64-2, math.MaxInt64, 1, true).(*Range)
+	require.Equal(t, "[9223372036854775805, 9223372036854775806, 9223372036854775807]", r.List().Inspect())
+	require.Equal(t, True, r.Contains(&Integer{Value: math.MaxInt64}))
+	require.Equal(t, False, r.Contains(&Integer{Value: math.MinInt64}))
+	require.Equal(t, "9223372036854775805..=9223372036854775807", r.Inspect())
+
+	r = NewRange(math.MinInt64, math.MaxInt64, math.MaxInt64, true).(*Range)
+	require.Equal(t, "[-9223372036854775808, -1, 9223372036854775806]", r.List().Inspect())
+	require.Equal(t, True, r.Contains(&Integer{Value: -1}))
+	require.Equal(t, False, r.Contains(&Integer{Value: math.MaxInt64}))
+}
@@ -0,0 +1,74 @@
+package object
+
+type sliceable interface {
+	sliceLen() int
+	slice(indices []int) Object
+}
+
+// Slice returns a new object holding the elements of obj from start up
+// to, but not including, end taking every step'th element. Bounds that
+// are nil are omitted, negative bounds count from the end and bounds
+// past either end are clamped.
+func Slice(obj Object, start, end, step Object) Object {
+	seq, ok := obj.(sliceable)
+	if !ok {
+		return NewTypeError("object is not sliceable: %s", obj.Type())
+	}
+	bounds := []*int{nil, nil, nil}
+	for k, bound := range []Object{start, end, step} {
+		if bound == nil {
+			continue
+		}
+		integer, ok := bound.(*Integer)
+		if !ok {
+			return NewTypeError("slice indices must be int, got %s", bound.Type())
+		}
+		value := int(integer.Value)
+		bounds[k] = &value
+	}
+	stride := 1
+	if bounds[2] != nil {
+		stride = *bounds[2]
+	}
+	if stride == 0 {
+		return &Error{ErrorType: ErrorTypeValueError, Message: "slice step cannot be zero"}
+	}
+	return seq.slice(sliceIndices(seq.sliceLen(), bounds[0], bounds[1], stride))
+}
+
+// sliceIndices resolves the bounds of a slice over a sequence of length
+// elements into the indices it selects
+func sliceIndices(length int, start, end *int, step int) []int {
+	lower, upper := 0, length
+	if step < 0 {
+		lower, upper = -1, length-1
+	}
+	clamp := func(bound *int, fallback int) int {
+		if bound == nil {
+			return fallback
+		}
+		k := *bound
+		if k < 0 {
+			k += length
+		}
+		if k < lower {
+			return lower
+		}
+		if k > upper {
+			return upper
+		}
+		return k
+	}
+
+	indices := make([]int, 0)
+	if step > 0 {
+		for k := clamp(start, lower); k < clamp(end, upper); k += step {
+			indices = append(indices, k)
+		}
+	} else {
+		for k := clamp(start, upper); k > clamp(end, lower); k += step {
+			indices = append(indices, k)
+		}
+	}
+	return indices
+}
@@ -0,0 +1,241 @@
+package object
+
+import (
+	"context"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxRepeatLength is the length of the longest string strings.repeat
+// makes
+const maxRepeatLength = 1 << 30
+
+// The functions below build the members of the native modules registered
+// by the evaluator. Each is called the first time its module is imported.
+
+func MathModule() map[string]Object {
+	return map[string]Object{
+		"max_int": &Integer{Value: math.MaxInt64},
+		"min_int": &Integer{Value: math.MinInt64},
+		"abs": &Builtin{Fn: func(args ...Object) Object {
+			if err := checkArgs("math.abs", args, 1, 1); err != nil {
+				return err
+			}
+			x, err := integerArg("math.abs", args, 0)
+			if err != nil {
+				return err
+			}
+			if x < 0 {
+				x = -x
+			}
+			return &Integer{Value: x}
+		}},
+		// sqrt returns the integer square root, rounded down
+		"sqrt": &Builtin{Fn: func(args ...Object) Object {
+			if err := checkArgs("math.sqrt", args, 1, 1); err != nil {
+				return err
+			}
+			x, err := integerArg("math.sqrt", args, 0)
+			if err != nil {
+				return err
+			}
+			if x < 0 {
+				return &Error{ErrorType: ErrorTypeValueError, Message: "math.sqrt of negative number"}
+			}
+			root := int64(math.Sqrt(float64(x)))
+			// correct for rounding in the float conversion, dividing
+			// instead of squaring so the checks can't overflow
+			for root > 0 && root > x/root {
+				root--
+			}
+			for root+1 <= x/(root+1) {
+				root++
+			}
+			return &Integer{Value: root}
+		}},
+		"pow": &Builtin{Fn: func(args ...Object) Object {
+			if err := checkArgs("math.pow", args, 2, 2); err != nil {
+				return err
+			}
+			base, err := integerArg("math.pow", args, 0)
+			if err != nil {
+				return err
+			}
+			exp, err := integerArg("math.pow", args, 1)
+			if err != nil {
+				return err
+			}
+			if exp < 0 {
+				return &Error{ErrorType: ErrorTypeValueError, Message: "math.pow with negative exponent"}
+			}
+			// exponentiation by squaring
+			result := int64(1)
+			for ; exp > 0; exp >>= 1 {
+				if exp&1 == 1 {
+					result *= base
+				}
+				base *= base
+			}
+			return &Integer{Value: result}
+		}},
+		"min": &Builtin{ContextFn: func(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+			if err := checkKeywords(kwargs); err != nil {
+				return err
+			}
+			return extreme(ctx, "math.min", Lt, args)
+		}},
+		"max": &Builtin{ContextFn: func(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+			if err := checkKeywords(kwargs); err != nil {
+				return err
+			}
+			return extreme(ctx, "math.max", Gt, args)
+		}},
+	}
+}
+
+// extreme returns the argument for which better holds against every other
+// argument
+func extreme(ctx context.Context, name string, better BinaryOpFunc, args []Object) Object {
+	if len(args) == 0 {
+		return NewTypeError("%s expected at least 1 positional argument", name)
+	}
+	best := args[0]
+	for _, arg := range args[1:] {
+		out := better(ctx, arg, best)
+		if isError(out) {
+			return out
+		}
+		if out == True {
+			best = arg
+		}
+	}
+	return best
+}
+
+func StringsModule() map[string]Object {
+	return map[string]Object{
+		"join": &Builtin{Fn: func(args ...Object) Object {
+			if err := checkArgs("strings.join", args, 2, 2); err != nil {
+				return err
+			}
+			list, ok := args[0].(*List)
+			if !ok {
+				return NewTypeError(
+					"strings.join expected positional argument 1 to be type %s but received type %s",
+					TypeList, args[0].Type(),
+				)
+			}
+			sep, err := stringArg("strings.join", args, 1)
+			if err != nil {
+				return err
+			}
+			values := list.Values()
+			parts := make([]string, 0, len(values))
+			for _, value := range values {
+				s, ok := value.(*String)
+				if !ok {
+					return NewTypeError("strings.join expected a list of %s, found %s", TypeString, value.Type())
+				}
+				parts = append(parts, s.Value)
+			}
+			return &String{Value: strings.Join(parts, sep)}
+		}},
+		"repeat": &Builtin{Fn: func(args ...Object) Object {
+			if err := checkArgs("strings.repeat", args, 2, 2); err != nil {
+				return err
+			}
+			s, err := stringArg("strings.repeat", args, 0)
+			if err != nil {
+				return err
+			}
+			n, err := integerArg("strings.repeat", args, 1)
+			if err != nil {
+				return err
+			}
+			if n < 0 {
+				return &Error{ErrorType: ErrorTypeValueError, Message: "strings.repeat with negative count"}
+			}
+			if len(s) > 0 && n > maxRepeatLength/int64(len(s)) {
+				return &Error{ErrorType: ErrorTypeValueError, Message: "strings.repeat result is too long"}
+			}
+			return &String{Value: strings.Repeat(s, int(n))}
+		}},
+		"count": &Builtin{Fn: func(args ...Object) Object {
+			if err := checkArgs("strings.count", args, 2, 2); err != nil {
+				return err
+			}
+			s, err := stringArg("strings.count", args, 0)
+			if err != nil {
+				return err
+			}
+			sub, err := stringArg("strings.count", args, 1)
+			if err != nil {
+				return err
+			}
+			return &Integer{Value: int64(strings.Count(s, sub))}
+		}},
+	}
+}
+
+func TimeModule() map[string]Object {
+	return map[string]Object{
+		// now returns the number of milliseconds since the Unix epoch
+		"now": &Builtin{Fn: func(args ...Object) Object {
+			if err := checkArgs("time.now", args, 0, 0); err != nil {
+				return err
+			}
+			return &Integer{Value: time.Now().UnixMilli()}
+		}},
+		// sleep waits for the given number of milliseconds, returning early
+		// if the caller is cancelled
+		"sleep": &Builtin{ContextFn: func(ctx context.Context, kwargs map[string]Object, args ...Object) Object {
+			if err := checkKeywords(kwargs); err != nil {
+				return err
+			}
+			if err := checkArgs("time.sleep", args, 1, 1); err != nil {
+				return err
+			}
+			ms, err := integerArg("time.sleep", args, 0)
+			if err != nil {
+				return err
+			}
+			timer := time.NewTimer(time.Duration(ms) * time.Millisecond)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				return NullValue
+			case <-ctx.Done():
+				return NewCancelledError(ctx.Err())
+			}
+		}},
+	}
+}
+
+func OSModule() map[string]Object {
+	args := make([]Object, 0, len(os.Args))
+	for _, arg := range os.Args {
+		args = append(args, &String{Value: arg})
+	}
+	return map[string]Object{
+		"args": &List{elements: args},
+		// getenv returns the value of an environment variable, or null when
+		// it isn't set
+		"getenv": &Builtin{Fn: func(args ...Object) Object {
+			if err := checkArgs("os.getenv", args, 1, 1); err != nil {
+				return err
+			}
+			name, err := stringArg("os.getenv", args, 0)
+			if err != nil {
+				return err
+			}
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				return NullValue
+			}
+			return &String{Value: value}
+		}},
+		"exit": &Builtin{Fn: BuiltinExit},
+	}
+}
@@ -1,6 +1,10 @@
 package object
 
-import "bytes"
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
 
 type String struct {
 	Value string
@@ -18,8 +22,27 @@ func (s *String) Add(other Object) Object {
 	return s.add(s2)
 }
 
+func (s *String) Eq(other Object) Object {
+	o, ok := other.(*String)
+	if !ok {
+		return nil
+	}
+	return nativeBool(s.Value == o.Value)
+}
+
+// Lt compares strings lexicographically by byte
+func (s *String) Lt(other Object) Object {
+	o, ok := other.(*String)
+	if !ok {
+		return nil
+	}
+	return nativeBool(s.Value < o.Value)
+}
+
+// length counts characters rather than bytes, like indexing, slicing
+// and iteration
 func (s *String) length() *Integer {
-	l := len(s.Value)
+	l := utf8.RuneCountInString(s.Value)
 	return &Integer{Value: int64(l)}
 }
 
@@ -30,13 +53,46 @@ func (s *String) list() *List {
 	for _, b := range s.Value {
 		values = append(values, &String{Value: string(b)})
 	}
-	return &List{Values: values}
+	return &List{elements: values}
 }
 
 func (s *String) List() Object { return s.list() }
 
+func (s *String) sliceLen() int { return utf8.RuneCountInString(s.Value) }
+
+// At returns the character at index k
+func (s *String) At(k int) Object {
+	return &String{Value: string([]rune(s.Value)[k])}
+}
+
+// slice selects characters rather than bytes
+func (s *String) slice(indices []int) Object {
+	runes := []rune(s.Value)
+	out := make([]rune, 0, len(indices))
+	for _, k := range indices {
+		out = append(out, runes[k])
+	}
+	return &String{Value: string(out)}
+}
+
+// Contains reports whether sub is a substring of s
+func (s *String) Contains(sub Object) Object {
+	o, ok := sub.(*String)
+	if !ok {
+		return NewTypeError("'in %s' requires %s as left operand, not %s", TypeString, TypeString, sub.Type())
+	}
+	if strings.Contains(s.Value, o.Value) {
+		return True
+	}
+	return False
+}
+
+func (s *String) Iter() Iterator { return &sliceIterator{values: s.list().Values()} }
+
 func (s *String) Type() Type { return TypeString }
 
+func (s *String) HashKey() HashKey { return HashKey{Type: TypeString, Value: s.Value} }
+
 func (s *String) Inspect() string {
 	out := new(bytes.Buffer)
 	out.WriteByte('"')
@@ -47,3 +103,79 @@ func (s *String) Inspect() string {
 
 var _ Object = &String{}
 var _ addend = &String{}
+
+var stringMethods = map[string]Method{
+	"split": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("split", args, 0, 1); err != nil {
+			return err
+		}
+		s := receiver.(*String).Value
+		var parts []string
+		if len(args) == 0 {
+			parts = strings.Fields(s)
+		} else {
+			sep, err := stringArg("split", args, 0)
+			if err != nil {
+				return err
+			}
+			parts = strings.Split(s, sep)
+		}
+		values := make([]Object, 0, len(parts))
+		for _, part := range parts {
+			values = append(values, &String{Value: part})
+		}
+		return &List{elements: values}
+	},
+	"trim": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("trim", args, 0, 0); err != nil {
+			return err
+		}
+		return &String{Value: strings.TrimSpace(receiver.(*String).Value)}
+	},
+	"upper": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("upper", args, 0, 0); err != nil {
+			return err
+		}
+		return &String{Value: strings.ToUpper(receiver.(*String).Value)}
+	},
+	"lower": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("lower", args, 0, 0); err != nil {
+			return err
+		}
+		return &String{Value: strings.ToLower(receiver.(*String).Value)}
+	},
+	"replace": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("replace", args, 2, 2); err != nil {
+			return err
+		}
+		old, err := stringArg("replace", args, 0)
+		if err != nil {
+			return err
+		}
+		replacement, err := stringArg("replace", args, 1)
+		if err != nil {
+			return err
+		}
+		return &String{Value: strings.ReplaceAll(receiver.(*String).Value, old, replacement)}
+	},
+	"starts_with": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("starts_with", args, 1, 1); err != nil {
+			return err
+		}
+		prefix, err := stringArg("starts_with", args, 0)
+		if err != nil {
+			return err
+		}
+		return nativeBool(strings.HasPrefix(receiver.(*String).Value, prefix))
+	},
+	"ends_with": func(receiver Object, args ...Object) Object {
+		if err := checkArgs("ends_with", args, 1, 1); err != nil {
+			return err
+		}
+		suffix, err := stringArg("ends_with", args, 0)
+		if err != nil {
+			return err
+		}
+		return nativeBool(strings.HasSuffix(receiver.(*String).Value, suffix))
+	},
+}
@@ -15,6 +15,8 @@ func TestString_Len(t *testing.T) {
 		{"12345", 5},
 		{"", 0},
 		{"ffffffffff", 10},
+		{"héllo", 5},
+		{"日本語", 3},
 	}
 
 	for _, subtest := range tests {
@@ -48,7 +50,7 @@ func TestString_List(t *testing.T) {
 		t.Run(subtest.input, func(t *testing.T) {
 			s := &String{Value: subtest.input}
 			objs := s.List()
-			require.Equal(t, subtest.expected, objs.(*List).Values)
+			require.Equal(t, subtest.expected, objs.(*List).Values())
 		})
 	}
 }
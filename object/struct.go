@@ -0,0 +1,126 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const TypeStruct Type = "struct"
+
+// StructType is a user defined record type declared with
+//
+//	struct Point { x, y }
+//
+// Calling it constructs an Instance with a value for each field.
+type StructType struct {
+	Name    string
+	Fields  []string
+	Methods map[string]*Function
+}
+
+func (s *StructType) Type() Type      { return TypeStruct }
+func (s *StructType) Inspect() string { return "struct " + s.Name }
+
+// New constructs an instance of the struct from positional arguments, one
+// per field in declaration order
+func (s *StructType) New(args ...Object) Object {
+	if len(args) != len(s.Fields) {
+		return NewTypeError(
+			"%s expected %d positional arguments but received %d",
+			s.Name, len(s.Fields), len(args),
+		)
+	}
+	fields := make(map[string]Object, len(s.Fields))
+	for k, name := range s.Fields {
+		fields[name] = args[k]
+	}
+	return &Instance{Struct: s, fields: fields}
+}
+
+// Instance is a value of a StructType. Its type is the name of the struct.
+// Its fields are safe to use from several tasks at once.
+type Instance struct {
+	Struct *StructType
+	mu     sync.RWMutex
+	fields map[string]Object
+	frozen bool
+}
+
+// values returns the values of the fields in declaration order
+func (i *Instance) values() []Object {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	values := make([]Object, 0, len(i.Struct.Fields))
+	for _, name := range i.Struct.Fields {
+		values = append(values, i.fields[name])
+	}
+	return values
+}
+
+func (i *Instance) Type() Type { return Type(i.Struct.Name) }
+
+func (i *Instance) Inspect() string {
+	values := i.values()
+	fields := make([]string, 0, len(values))
+	for k, name := range i.Struct.Fields {
+		fields = append(fields, fmt.Sprintf("%s: %s", name, values[k].Inspect()))
+	}
+	out := new(bytes.Buffer)
+	out.WriteString(i.Struct.Name)
+	out.WriteString("{")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString("}")
+	return out.String()
+}
+
+// Field returns the value of the named field
+func (i *Instance) Field(name string) (Object, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	value, ok := i.fields[name]
+	return value, ok
+}
+
+// SetField replaces the value of an existing field. Structs can't gain new
+// fields after they are constructed.
+func (i *Instance) SetField(name string, value Object) Object {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.frozen {
+		return newFrozenError(i.Type())
+	}
+	if _, ok := i.fields[name]; !ok {
+		return &Error{
+			ErrorType: ErrorTypeKeyError,
+			Message:   fmt.Sprintf("%s has no field %s", i.Struct.Name, name),
+		}
+	}
+	i.fields[name] = value
+	return value
+}
+
+// Method returns the named method bound to the instance
+func (i *Instance) Method(name string) (*Function, bool) {
+	method, ok := i.Struct.Methods[name]
+	if !ok {
+		return nil, false
+	}
+	return method.Bind(i), true
+}
+
+func (i *Instance) Freeze() {
+	i.mu.Lock()
+	i.frozen = true
+	i.mu.Unlock()
+	for _, value := range i.values() {
+		Freeze(value)
+	}
+}
+
+func (i *Instance) Frozen() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.frozen
+}
@@ -0,0 +1,36 @@
+package object
+
+import "context"
+
+const TypeTask Type = "Task"
+
+// Task is a function call running on its own goroutine
+type Task struct {
+	done   chan struct{}
+	result Object
+}
+
+// Spawn starts fn on a new goroutine
+func Spawn(fn func() Object) *Task {
+	t := &Task{done: make(chan struct{})}
+	go func() {
+		defer close(t.done)
+		t.result = fn()
+	}()
+	return t
+}
+
+func (t *Task) Type() Type      { return TypeTask }
+func (t *Task) Inspect() string { return "<task>" }
+
+// Await waits for the call to return and returns its result. An error
+// raised by the call is returned to every caller of Await. Waiting stops
+// early if ctx is cancelled.
+func (t *Task) Await(ctx context.Context) Object {
+	select {
+	case <-t.done:
+		return t.result
+	case <-ctx.Done():
+		return NewCancelledError(ctx.Err())
+	}
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"mitchlang/ast"
+	"mitchlang/token"
+)
+
+// parseListComprehension parses the clauses of [element for x in xs]. The
+// next token is the first `for`.
+func (p *Parser) parseListComprehension(tok *token.Token, element ast.Expression) ast.Expression {
+	expression := &ast.ListComprehension{Token: tok, Element: element}
+
+	expression.Clauses = p.parseComprehensionClauses()
+	if expression.Clauses == nil {
+		return nil
+	}
+	if !p.expectNext(token.RBracket) {
+		return nil
+	}
+	return expression
+}
+
+// parseMapComprehension parses the clauses of {key: value for x in xs}.
+// The next token is the first `for`.
+func (p *Parser) parseMapComprehension(
+	tok *token.Token,
+	key ast.Expression,
+	value ast.Expression,
+) ast.Expression {
+	expression := &ast.MapComprehension{Token: tok, Key: key, Value: value}
+
+	expression.Clauses = p.parseComprehensionClauses()
+	if expression.Clauses == nil {
+		return nil
+	}
+	if !p.expectNext(token.RBrace) {
+		return nil
+	}
+	return expression
+}
+
+func (p *Parser) parseComprehensionClauses() []*ast.ComprehensionClause {
+	clauses := []*ast.ComprehensionClause{}
+
+	for p.next.IsType(token.For) {
+		p.nextToken()
+		clause := &ast.ComprehensionClause{Token: p.current}
+		clause.Conditions = []ast.Expression{}
+
+		p.nextToken()
+		clause.Pattern = p.parseComprehensionPattern()
+		if clause.Pattern == nil {
+			return nil
+		}
+		if !p.expectNext(token.In) {
+			return nil
+		}
+		p.nextToken()
+		clause.Iterable = p.parseExpression(Lowest)
+		if clause.Iterable == nil {
+			return nil
+		}
+		for p.next.IsType(token.If) {
+			p.nextToken()
+			p.nextToken()
+			condition := p.parseExpression(Lowest)
+			if condition == nil {
+				return nil
+			}
+			clause.Conditions = append(clause.Conditions, condition)
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
+
+// parseComprehensionPattern parses the loop variables of a comprehension
+// clause. `for k, v in xs` is shorthand for `for [k, v] in xs`.
+func (p *Parser) parseComprehensionPattern() ast.Pattern {
+	tok := p.current
+	pattern := p.parsePattern()
+	if pattern == nil || !p.next.IsType(token.Comma) {
+		return pattern
+	}
+	list := &ast.ListPattern{Token: tok, Elements: []ast.Pattern{pattern}}
+	for p.next.IsType(token.Comma) {
+		p.nextToken()
+		p.nextToken()
+		element := p.parsePattern()
+		if element == nil {
+			return nil
+		}
+		list.Elements = append(list.Elements, element)
+	}
+	return list
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/token"
+)
+
+func (p *Parser) parseSpawnExpression() ast.Expression {
+	expression := &ast.SpawnExpression{Token: p.current}
+	p.nextToken()
+	call, ok := p.parseExpression(Prefix).(*ast.CallExpression)
+	if !ok {
+		p.errors = append(p.errors, "spawn expects a function call")
+		return nil
+	}
+	expression.Call = call
+	return expression
+}
+
+func (p *Parser) parseAwaitExpression() ast.Expression {
+	expression := &ast.AwaitExpression{Token: p.current}
+	p.nextToken()
+	expression.Value = p.parseExpression(Prefix)
+	if expression.Value == nil {
+		return nil
+	}
+	return expression
+}
+
+func (p *Parser) parseSelectExpression() ast.Expression {
+	expression := &ast.SelectExpression{Token: p.current}
+	expression.Cases = []*ast.SelectCase{}
+
+	if !p.expectNext(token.LBrace) {
+		return nil
+	}
+	p.nextToken()
+	hasDefault := false
+	for !p.current.IsType(token.RBrace) {
+		if p.current.IsType(token.EOF) {
+			p.errors = append(p.errors, "unterminated select expression")
+			return nil
+		}
+		c := p.parseSelectCase()
+		if c == nil {
+			return nil
+		}
+		if c.Kind == ast.SelectDefault {
+			if hasDefault {
+				p.errors = append(p.errors, "select has more than one default case")
+				return nil
+			}
+			hasDefault = true
+		}
+		expression.Cases = append(expression.Cases, c)
+		for p.next.IsType(token.Comma) || p.next.IsType(token.SemiColon) {
+			p.nextToken()
+		}
+		p.nextToken()
+	}
+	return expression
+}
+
+// parseSelectCase parses a case of a select expression. recv, send and
+// default are only keywords at the start of a case.
+func (p *Parser) parseSelectCase() *ast.SelectCase {
+	c := &ast.SelectCase{Token: p.current}
+	if !p.current.IsType(token.Ident) {
+		p.errors = append(p.errors, fmt.Sprintf("unexpected token %s in select", p.current.Type))
+		return nil
+	}
+	if p.next.IsType(token.Assign) && p.next.Literal == "=" {
+		c.Name = &ast.Identifier{Token: p.current, Value: p.current.Literal}
+		p.nextToken()
+		if !p.expectNext(token.Ident) {
+			return nil
+		}
+		if p.current.Literal != ast.SelectRecv {
+			p.errors = append(p.errors, fmt.Sprintf("expected recv, got %s", p.current.Literal))
+			return nil
+		}
+	}
+
+	c.Kind = p.current.Literal
+	switch c.Kind {
+	case ast.SelectDefault:
+	case ast.SelectRecv, ast.SelectSend:
+		if !p.expectNext(token.LParen) {
+			return nil
+		}
+		args := p.parseSelectArguments()
+		if args == nil {
+			return nil
+		}
+		expected := 1
+		if c.Kind == ast.SelectSend {
+			expected = 2
+		}
+		if len(args) != expected {
+			p.errors = append(
+				p.errors,
+				fmt.Sprintf("%s expects %d arguments, got %d", c.Kind, expected, len(args)),
+			)
+			return nil
+		}
+		c.Channel = args[0]
+		if c.Kind == ast.SelectSend {
+			c.Value = args[1]
+		}
+	default:
+		p.errors = append(
+			p.errors,
+			fmt.Sprintf("expected recv, send or default in select, got %s", c.Kind),
+		)
+		return nil
+	}
+
+	if !p.expectNext(token.Arrow) {
+		return nil
+	}
+	p.nextToken()
+	c.Body = p.parseArmBody()
+	if c.Body == nil {
+		return nil
+	}
+	return c
+}
+
+// parseSelectArguments parses the arguments of recv or send. The current
+// token is the opening parenthesis.
+func (p *Parser) parseSelectArguments() []ast.Expression {
+	args := []ast.Expression{}
+	p.nextToken()
+	for !p.current.IsType(token.RParen) {
+		if p.current.IsType(token.EOF) {
+			p.errors = append(p.errors, "unterminated argument list")
+			return nil
+		}
+		arg := p.parseExpression(Lowest)
+		if arg == nil {
+			return nil
+		}
+		args = append(args, arg)
+		if p.next.IsType(token.Comma) {
+			p.nextToken()
+		}
+		p.nextToken()
+	}
+	return args
+}
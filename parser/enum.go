@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/token"
+)
+
+// parseEnumStatement parses
+//
+//	enum Shape { Circle(r), Rect(w, h), Empty }
+func (p *Parser) parseEnumStatement() *ast.EnumStatement {
+	statement := &ast.EnumStatement{Token: p.current}
+	statement.Variants = []*ast.EnumVariant{}
+
+	if !p.expectNext(token.Ident) {
+		return nil
+	}
+	statement.Name = &ast.Identifier{Token: p.current, Value: p.current.Literal}
+	if !p.expectNext(token.LBrace) {
+		return nil
+	}
+	p.nextToken()
+	for !p.current.IsType(token.RBrace) {
+		switch p.current.Type {
+		case token.Ident:
+			variant := p.parseEnumVariant()
+			if variant == nil {
+				return nil
+			}
+			statement.Variants = append(statement.Variants, variant)
+		case token.Comma, token.SemiColon:
+		case token.EOF:
+			p.errors = append(p.errors, "unterminated enum declaration")
+			return nil
+		default:
+			p.errors = append(
+				p.errors,
+				fmt.Sprintf("unexpected token %s in enum declaration", p.current.Type),
+			)
+			return nil
+		}
+		p.nextToken()
+	}
+	if p.next.IsType(token.SemiColon) {
+		p.nextToken()
+	}
+	return statement
+}
+
+func (p *Parser) parseEnumVariant() *ast.EnumVariant {
+	variant := &ast.EnumVariant{Token: p.current}
+	variant.Name = &ast.Identifier{Token: p.current, Value: p.current.Literal}
+	if !p.next.IsType(token.LParen) {
+		return variant
+	}
+	p.nextToken()
+	variant.Fields = []*ast.Identifier{}
+
+	p.nextToken()
+	for !p.current.IsType(token.RParen) {
+		if !p.current.IsType(token.Ident) {
+			p.errors = append(
+				p.errors,
+				fmt.Sprintf("unexpected token %s in variant %s", p.current.Type, variant.Name),
+			)
+			return nil
+		}
+		field := &ast.Identifier{Token: p.current, Value: p.current.Literal}
+		variant.Fields = append(variant.Fields, field)
+		if p.next.IsType(token.Comma) {
+			p.nextToken()
+		}
+		p.nextToken()
+	}
+	return variant
+}
@@ -0,0 +1,26 @@
+package parser
+
+import (
+	"mitchlang/ast"
+)
+
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	expression := &ast.MacroLiteral{Token: p.current}
+	fn := &ast.FunctionLiteralExpression{Token: p.current}
+	if !p.parseFunction(fn) {
+		return nil
+	}
+	for _, pattern := range fn.Patterns {
+		if pattern != nil {
+			p.errors = append(p.errors, "macro parameters must be identifiers")
+			return nil
+		}
+	}
+	if fn.Generator {
+		p.errors = append(p.errors, "yield inside a macro")
+		return nil
+	}
+	expression.Parameters = fn.Parameters
+	expression.Body = fn.Body
+	return expression
+}
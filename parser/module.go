@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/token"
+	"path"
+	"strings"
+)
+
+// parseImportStatement parses `import "path" as name;`. Without an alias
+// the module is bound to the last element of its path.
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	statement := &ast.ImportStatement{Token: p.current}
+	if !p.expectNext(token.String) {
+		return nil
+	}
+	statement.Path = &ast.StringLiteral{Token: p.current, Value: p.current.Literal}
+
+	// as is only a keyword directly after the path of an import
+	if p.next.IsType(token.Ident) && p.next.Literal == "as" {
+		p.nextToken()
+		if !p.expectNext(token.Ident) {
+			return nil
+		}
+		statement.Alias = &ast.Identifier{Token: p.current, Value: p.current.Literal}
+	} else {
+		name := strings.TrimSuffix(path.Base(statement.Path.Value), path.Ext(statement.Path.Value))
+		statement.Alias = &ast.Identifier{Token: statement.Path.Token, Value: name}
+	}
+	if p.next.IsType(token.SemiColon) {
+		p.nextToken()
+	}
+	return statement
+}
+
+func (p *Parser) parseExportStatement() *ast.ExportStatement {
+	statement := &ast.ExportStatement{Token: p.current}
+	doc := p.current.Doc
+	p.nextToken()
+	switch p.current.Type {
+	case token.Let, token.Const, token.Struct, token.Enum:
+	default:
+		p.errors = append(
+			p.errors,
+			fmt.Sprintf("expected declaration after export, got %s", p.current.Type),
+		)
+		return nil
+	}
+	statement.Statement = p.parseStatement()
+	if statement.Statement == nil {
+		return nil
+	}
+	// doc comments are written before export rather than the declaration
+	if let, ok := statement.Statement.(*ast.LetStatement); ok {
+		attachDoc(let, doc)
+	}
+	return statement
+}
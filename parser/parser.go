@@ -11,27 +11,41 @@ import (
 const (
 	_ int = iota
 	Lowest
+	Assignment  // =
+	Coalesce    // ??
 	Equals      // ==
 	LessGreater // > or <
+	Range       // 0..10
 	Sum         // +
 	Product     // *
 	Prefix      // -X or !X
+	Postfix     // X?
 	Call
 	Index
 )
 
 var (
 	precedences = map[token.Type]int{
-		token.Eq:       Equals,
-		token.NotEq:    Equals,
-		token.LT:       LessGreater,
-		token.GT:       LessGreater,
-		token.Plus:     Sum,
-		token.Minus:    Sum,
-		token.Slash:    Product,
-		token.Asterisk: Product,
-		token.LParen:   Call,
-		token.LBracket: Index,
+		token.Eq:            Equals,
+		token.NotEq:         Equals,
+		token.In:            Equals,
+		token.Not:           Equals,
+		token.Is:            Equals,
+		token.LT:            LessGreater,
+		token.GT:            LessGreater,
+		token.Range:         Range,
+		token.RangeEq:       Range,
+		token.Plus:          Sum,
+		token.Minus:         Sum,
+		token.Slash:         Product,
+		token.Asterisk:      Product,
+		token.Question:      Postfix,
+		token.LParen:        Call,
+		token.LBracket:      Index,
+		token.OptionalIndex: Index,
+		token.OptionalDot:   Index,
+		token.Dot:           Index,
+		token.Coalesce:      Coalesce,
 	}
 )
 
@@ -45,23 +59,36 @@ type Parser struct {
 	current *token.Token
 	next    *token.Token
 	errors  []string
+	// illegal is the last Illegal token reported, so that a token seen
+	// both as the next and the current token is reported once
+	illegal *token.Token
+	// generators records whether each function being parsed contains a
+	// yield, innermost last
+	generators []bool
 
 	prefixFuncs map[token.Type]prefixFunc
 	infixFuncs  map[token.Type]infixFunc
 }
 
-func (p *Parser) peekPrecedence() int {
-	if p, ok := precedences[p.next.Type]; ok {
+// precedenceOf returns the binding power of tok when used as an infix
+// operator. token.Eq and token.Assign share a type, so "=" is told apart
+// from "==" by its literal.
+func precedenceOf(tok *token.Token) int {
+	if tok.IsType(token.Assign) && tok.Literal == "=" {
+		return Assignment
+	}
+	if p, ok := precedences[tok.InfixType()]; ok {
 		return p
 	}
 	return Lowest
 }
 
+func (p *Parser) peekPrecedence() int {
+	return precedenceOf(p.next)
+}
+
 func (p *Parser) currentPrecedence() int {
-	if p, ok := precedences[p.current.Type]; ok {
-		return p
-	}
-	return Lowest
+	return precedenceOf(p.current)
 }
 
 func (p *Parser) registerPrefix(tType token.Type, fn prefixFunc) {
@@ -78,6 +105,10 @@ func (p *Parser) nextToken() {
 }
 
 func (p *Parser) expectNext(tokenType token.Type) bool {
+	if p.next.IsType(token.Illegal) {
+		p.illegalError(p.next)
+		return false
+	}
 	if !p.next.IsType(tokenType) {
 		p.errors = append(
 			p.errors,
@@ -93,6 +124,16 @@ func (p *Parser) expectNext(tokenType token.Type) bool {
 	return true
 }
 
+// illegalError reports an Illegal token, whose literal describes the
+// problem found by the lexer
+func (p *Parser) illegalError(tok *token.Token) {
+	if tok == p.illegal {
+		return
+	}
+	p.illegal = tok
+	p.errors = append(p.errors, tok.Literal)
+}
+
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	statement := &ast.ReturnStatement{Token: p.current}
 	if !p.current.IsType(token.Return) {
@@ -112,13 +153,21 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	statement := &ast.LetStatement{Token: p.current}
 
-	// let x
-	if !p.expectNext(token.Ident) {
-		return nil
+	if p.next.IsType(token.LBracket) || p.next.IsType(token.LBrace) {
+		// let [a, b] or let {a, b}
+		p.nextToken()
+		statement.Pattern = p.parsePattern()
+		if statement.Pattern == nil {
+			return nil
+		}
+	} else {
+		// let x
+		if !p.expectNext(token.Ident) {
+			return nil
+		}
+		statement.Name = &ast.Identifier{Token: p.current, Value: p.current.Literal}
 	}
 
-	statement.Name = &ast.Identifier{Token: p.current, Value: p.current.Literal}
-
 	// let x =
 	if !p.expectNext(token.Assign) {
 		return nil
@@ -126,6 +175,7 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 
 	p.nextToken()
 	statement.Value = p.parseExpression(Lowest)
+	attachDoc(statement, statement.Token.Doc)
 	// TODO: should this be required?
 	if p.next.IsType(token.SemiColon) {
 		p.nextToken()
@@ -133,12 +183,169 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 	return statement
 }
 
+// attachDoc sets the doc comments of a let statement, and of the function
+// it declares, unless they already have their own
+func attachDoc(statement *ast.LetStatement, doc string) {
+	if statement.Doc == "" {
+		statement.Doc = doc
+	}
+	if fn, ok := statement.Value.(*ast.FunctionLiteralExpression); ok && fn.Doc == "" {
+		fn.Doc = statement.Doc
+	}
+}
+
+func (p *Parser) parseForStatement() *ast.ForStatement {
+	statement := &ast.ForStatement{Token: p.current}
+
+	if !p.expectNext(token.LParen) {
+		return nil
+	}
+	p.nextToken()
+	statement.Pattern = p.parsePattern()
+	if statement.Pattern == nil {
+		return nil
+	}
+	if !p.expectNext(token.In) {
+		return nil
+	}
+	p.nextToken()
+	statement.Iterable = p.parseExpression(Lowest)
+	if statement.Iterable == nil {
+		return nil
+	}
+	if !p.expectNext(token.RParen) {
+		return nil
+	}
+	if !p.expectNext(token.LBrace) {
+		return nil
+	}
+	statement.Body = p.parseBlockStatement()
+	return statement
+}
+
+func (p *Parser) parseDeferStatement() *ast.DeferStatement {
+	statement := &ast.DeferStatement{Token: p.current}
+	p.nextToken()
+
+	statement.Expression = p.parseExpression(Lowest)
+	if statement.Expression == nil {
+		return nil
+	}
+	if p.next.IsType(token.SemiColon) {
+		p.nextToken()
+	}
+	return statement
+}
+
+func (p *Parser) parseYieldStatement() *ast.YieldStatement {
+	statement := &ast.YieldStatement{Token: p.current}
+	if len(p.generators) == 0 {
+		p.errors = append(p.errors, "yield outside of a function")
+		return nil
+	}
+	p.generators[len(p.generators)-1] = true
+	p.nextToken()
+
+	statement.Value = p.parseExpression(Lowest)
+	if statement.Value == nil {
+		return nil
+	}
+	if p.next.IsType(token.SemiColon) {
+		p.nextToken()
+	}
+	return statement
+}
+
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	statement := &ast.ThrowStatement{Token: p.current}
+	p.nextToken()
+
+	statement.Value = p.parseExpression(Lowest)
+	if statement.Value == nil {
+		return nil
+	}
+	if p.next.IsType(token.SemiColon) {
+		p.nextToken()
+	}
+	return statement
+}
+
+func (p *Parser) parseTryStatement() *ast.TryStatement {
+	statement := &ast.TryStatement{Token: p.current}
+	statement.Catches = []*ast.CatchClause{}
+
+	if !p.expectNext(token.LBrace) {
+		return nil
+	}
+	statement.Block = p.parseBlockStatement()
+
+	for p.next.IsType(token.Catch) {
+		p.nextToken()
+		clause := p.parseCatchClause()
+		if clause == nil {
+			return nil
+		}
+		statement.Catches = append(statement.Catches, clause)
+	}
+	if p.next.IsType(token.Finally) {
+		p.nextToken()
+		if !p.expectNext(token.LBrace) {
+			return nil
+		}
+		statement.Finally = p.parseBlockStatement()
+	}
+	if len(statement.Catches) == 0 && statement.Finally == nil {
+		p.errors = append(p.errors, "expected catch or finally after try block")
+		return nil
+	}
+	return statement
+}
+
+// parseCatchClause parses catch { }, catch (e) { } and catch (Type e) { }
+func (p *Parser) parseCatchClause() *ast.CatchClause {
+	clause := &ast.CatchClause{Token: p.current}
+
+	if p.next.IsType(token.LParen) {
+		p.nextToken()
+		if !p.expectNext(token.Ident) {
+			return nil
+		}
+		clause.Name = &ast.Identifier{Token: p.current, Value: p.current.Literal}
+		if p.next.IsType(token.Ident) {
+			p.nextToken()
+			clause.ErrorType = clause.Name
+			clause.Name = &ast.Identifier{Token: p.current, Value: p.current.Literal}
+		}
+		if !p.expectNext(token.RParen) {
+			return nil
+		}
+	}
+	if !p.expectNext(token.LBrace) {
+		return nil
+	}
+	clause.Body = p.parseBlockStatement()
+	return clause
+}
+
 func (p *Parser) parseCallExpression(left ast.Expression) ast.Expression {
 	call := &ast.CallExpression{Token: p.current, Function: left}
 	call.Arguments = []ast.Expression{}
 	p.nextToken()
 	for !p.current.IsType(token.RParen) {
+		if p.current.IsType(token.EOF) {
+			p.errors = append(p.errors, "unterminated argument list")
+			return nil
+		}
 		arg := p.parseExpression(Lowest)
+		// name = value passes the argument by name
+		if assign, ok := arg.(*ast.AssignExpression); ok {
+			if name, ok := assign.Target.(*ast.Identifier); ok {
+				call.Keywords = append(call.Keywords, &ast.KeywordArgument{Name: name, Value: assign.Value})
+				arg = nil
+			}
+		} else if arg != nil && len(call.Keywords) > 0 {
+			p.errors = append(p.errors, "positional argument follows keyword argument")
+		}
 		if arg != nil {
 			call.Arguments = append(call.Arguments, arg)
 		}
@@ -155,6 +362,14 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	// let <identifier> = <prefix-operator | expression> <infix-operator> <expression>;
 	// prefix can be anything
 	prefix := p.prefixFuncs[p.current.Type]
+	if prefix == nil && p.current.IsType(token.EOF) {
+		p.errors = append(p.errors, "unexpected end of input")
+		return nil
+	}
+	if p.current.IsType(token.Illegal) {
+		p.illegalError(p.current)
+		return nil
+	}
 	if prefix == nil {
 		p.errors = append(
 			p.errors,
@@ -167,7 +382,7 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		return nil
 	}
 	for !p.next.IsType(token.SemiColon) && precedence < p.peekPrecedence() {
-		infix := p.infixFuncs[p.next.Type]
+		infix := p.infixFuncs[p.next.InfixType()]
 		if infix == nil {
 			return left
 		}
@@ -208,7 +423,7 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.current.Type {
-	case token.Let:
+	case token.Let, token.Const:
 		if stmt := p.parseLetStatement(); stmt != nil {
 			return stmt
 		}
@@ -218,6 +433,66 @@ func (p *Parser) parseStatement() ast.Statement {
 			return stmt
 		}
 		return nil
+	case token.For:
+		if stmt := p.parseForStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.Struct:
+		if stmt := p.parseStructStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.Enum:
+		if stmt := p.parseEnumStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.Import:
+		if stmt := p.parseImportStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.Export:
+		if stmt := p.parseExportStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.Defer:
+		if stmt := p.parseDeferStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.Yield:
+		if stmt := p.parseYieldStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.Throw:
+		if stmt := p.parseThrowStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.Try:
+		if stmt := p.parseTryStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
+	case token.Ident:
+		// group is only a keyword at the start of a statement
+		if p.current.Literal == "group" && p.next.IsType(token.LBrace) {
+			statement := &ast.GroupStatement{Token: p.current}
+			p.nextToken()
+			statement.Block = p.parseBlockStatement()
+			if p.next.IsType(token.SemiColon) {
+				p.nextToken()
+			}
+			return statement
+		}
+		if stmt := p.parseExpressionStatement(); stmt != nil {
+			return stmt
+		}
+		return nil
 	default:
 		if stmt := p.parseExpressionStatement(); stmt != nil {
 			return stmt
@@ -273,6 +548,39 @@ func (p *Parser) parseString() ast.Expression {
 	return &ast.StringLiteral{Token: p.current, Value: p.current.Literal}
 }
 
+// parseInterpolatedString parses the source of each expression embedded
+// in the string on its own
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	expression := &ast.InterpolatedString{Token: p.current}
+	for k, part := range p.current.Parts {
+		if k%2 == 0 {
+			if part != "" {
+				text := token.NewFromString(token.String, part)
+				expression.Parts = append(expression.Parts, &ast.StringLiteral{Token: text, Value: part})
+			}
+			continue
+		}
+		embedded := New(lexer.New(part))
+		program := embedded.ParseProgram()
+		for _, err := range embedded.Errors() {
+			p.errors = append(p.errors, fmt.Sprintf("in ${%s}: %s", part, err))
+		}
+		if len(embedded.Errors()) > 0 {
+			return nil
+		}
+		var statement *ast.ExpressionStatement
+		if len(program.Statements) == 1 {
+			statement, _ = program.Statements[0].(*ast.ExpressionStatement)
+		}
+		if statement == nil {
+			p.errors = append(p.errors, fmt.Sprintf("${%s} must hold a single expression", part))
+			return nil
+		}
+		expression.Parts = append(expression.Parts, statement.Expression)
+	}
+	return expression
+}
+
 func (p *Parser) parseBoolean() ast.Expression {
 	if !p.current.IsType(token.True) && !p.current.IsType(token.False) {
 		p.errors = append(
@@ -287,6 +595,10 @@ func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.current, Value: false}
 }
 
+func (p *Parser) parseNull() ast.Expression {
+	return &ast.NullLiteral{Token: p.current}
+}
+
 func (p *Parser) parseGroupedExpression() ast.Expression {
 	p.nextToken()
 
@@ -314,6 +626,37 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseRangeExpression(left ast.Expression) ast.Expression {
+	expression := &ast.RangeExpression{
+		Token:     p.current,
+		Start:     left,
+		Inclusive: p.current.IsType(token.RangeEq),
+	}
+	p.nextToken()
+	expression.End = p.parseExpression(Range)
+	if expression.End == nil {
+		return nil
+	}
+	// step is only a keyword directly after a range
+	if p.next.IsType(token.Ident) && p.next.Literal == "step" {
+		p.nextToken()
+		p.nextToken()
+		expression.Step = p.parseExpression(Range)
+		if expression.Step == nil {
+			return nil
+		}
+	}
+	return expression
+}
+
+func (p *Parser) parsePostfixExpression(left ast.Expression) ast.Expression {
+	return &ast.PostfixExpression{
+		Token:    p.current,
+		Operator: p.current.Literal,
+		Left:     left,
+	}
+}
+
 func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	expression := &ast.InfixExpression{
 		Token:    p.current,
@@ -330,6 +673,73 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseEqualsOrAssign(left ast.Expression) ast.Expression {
+	if p.current.Literal == "=" {
+		return p.parseAssignExpression(left)
+	}
+	return p.parseInfixExpression(left)
+}
+
+func (p *Parser) parseAssignExpression(left ast.Expression) ast.Expression {
+	expression := &ast.AssignExpression{Token: p.current, Target: left}
+
+	switch target := left.(type) {
+	case *ast.Identifier:
+	case *ast.IndexExpression:
+		if target.Optional {
+			p.errors = append(p.errors, fmt.Sprintf("cannot assign to %s", left))
+			return nil
+		}
+	case *ast.MemberExpression:
+		if target.Optional {
+			p.errors = append(p.errors, fmt.Sprintf("cannot assign to %s", left))
+			return nil
+		}
+	default:
+		p.errors = append(p.errors, fmt.Sprintf("cannot assign to %s", left))
+		return nil
+	}
+
+	p.nextToken()
+	// assignment is right associative: a = b = c is a = (b = c)
+	expression.Value = p.parseExpression(Lowest)
+	if expression.Value == nil {
+		return nil
+	}
+	return expression
+}
+
+// parseNotInExpression parses `a not in b`
+func (p *Parser) parseNotInExpression(left ast.Expression) ast.Expression {
+	tok := p.current
+	if !p.expectNext(token.In) {
+		return nil
+	}
+	expression := p.parseInfixExpression(left)
+	if expression == nil {
+		return nil
+	}
+	expression.(*ast.InfixExpression).Token = tok
+	expression.(*ast.InfixExpression).Operator = "not in"
+	return expression
+}
+
+// parseIsExpression parses `a is b` and `a is not b`
+func (p *Parser) parseIsExpression(left ast.Expression) ast.Expression {
+	if p.next.InfixType() != token.Not {
+		return p.parseInfixExpression(left)
+	}
+	tok := p.current
+	p.nextToken()
+	expression := p.parseInfixExpression(left)
+	if expression == nil {
+		return nil
+	}
+	expression.(*ast.InfixExpression).Token = tok
+	expression.(*ast.InfixExpression).Operator = "is not"
+	return expression
+}
+
 func (p *Parser) parseIfExpression() ast.Expression {
 	expression := &ast.IfExpression{Token: p.current}
 
@@ -358,26 +768,54 @@ func (p *Parser) parseIfExpression() ast.Expression {
 }
 
 func (p *Parser) parseFunctionLiteralExpression() ast.Expression {
-	expression := &ast.FunctionLiteralExpression{Token: p.current}
+	expression := &ast.FunctionLiteralExpression{Token: p.current, Doc: p.current.Doc}
+	if !p.parseFunction(expression) {
+		return nil
+	}
+	return expression
+}
+
+// parseFunction parses the parameters and body of a function into
+// expression. The next token is the opening parenthesis.
+func (p *Parser) parseFunction(expression *ast.FunctionLiteralExpression) bool {
 	expression.Parameters = []*ast.Identifier{}
+	expression.Patterns = []ast.Pattern{}
 
 	if !p.expectNext(token.LParen) {
-		return nil
+		return false
 	}
 	p.nextToken()
 	for !p.current.IsType(token.RParen) {
+		if p.current.IsType(token.EOF) {
+			p.errors = append(p.errors, "unterminated parameter list")
+			return false
+		}
 		ident := &ast.Identifier{Token: p.current, Value: p.current.Literal}
+		var pattern ast.Pattern
+		if p.current.IsType(token.LBracket) || p.current.IsType(token.LBrace) {
+			pattern = p.parsePattern()
+			if pattern == nil {
+				return false
+			}
+			// destructured parameters are bound under a name that can't
+			// be referenced from the function body
+			ident.Value = pattern.String()
+		}
 		expression.Parameters = append(expression.Parameters, ident)
+		expression.Patterns = append(expression.Patterns, pattern)
 		if p.next.IsType(token.Comma) {
 			p.nextToken()
 		}
 		p.nextToken()
 	}
 	if !p.expectNext(token.LBrace) {
-		return nil
+		return false
 	}
+	p.generators = append(p.generators, false)
 	expression.Body = p.parseBlockStatement()
-	return expression
+	expression.Generator = p.generators[len(p.generators)-1]
+	p.generators = p.generators[:len(p.generators)-1]
+	return true
 }
 
 func (p *Parser) parseListExpression() ast.Expression {
@@ -387,6 +825,9 @@ func (p *Parser) parseListExpression() ast.Expression {
 	p.nextToken()
 	for !p.current.IsType(token.RBracket) && !p.next.IsType(token.EOF) {
 		item := p.parseExpression(Lowest)
+		if item != nil && len(expression.Items) == 0 && p.next.IsType(token.For) {
+			return p.parseListComprehension(expression.Token, item)
+		}
 		if item != nil {
 			expression.Items = append(expression.Items, item)
 		}
@@ -401,6 +842,7 @@ func (p *Parser) parseListExpression() ast.Expression {
 func (p *Parser) parseHashMapExpression() ast.Expression {
 	expression := &ast.MapExpression{Token: p.current}
 	expression.Entries = map[ast.Expression]ast.Expression{}
+	expression.Keys = []ast.Expression{}
 
 	p.nextToken()
 	for !p.current.IsType(token.RBrace) && !p.next.IsType(token.EOF) {
@@ -411,8 +853,12 @@ func (p *Parser) parseHashMapExpression() ast.Expression {
 			}
 			p.nextToken()
 			value := p.parseExpression(Lowest)
+			if value != nil && len(expression.Keys) == 0 && p.next.IsType(token.For) {
+				return p.parseMapComprehension(expression.Token, key, value)
+			}
 			if value != nil {
 				expression.Entries[key] = value
+				expression.Keys = append(expression.Keys, key)
 			}
 		}
 		if p.next.IsType(token.Comma) {
@@ -427,7 +873,14 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	expression := &ast.IndexExpression{Token: p.current, Left: left}
 
 	p.nextToken() // consume '['
+	if p.current.IsType(token.Colon) {
+		return p.parseSliceExpression(left, expression.Token, nil)
+	}
 	expression.Index = p.parseExpression(Lowest)
+	if p.next.IsType(token.Colon) {
+		p.nextToken()
+		return p.parseSliceExpression(left, expression.Token, expression.Index)
+	}
 
 	if !p.expectNext(token.RBracket) {
 		return nil
@@ -436,6 +889,81 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+// parseSliceExpression parses the remainder of a slice after its start
+// bound. The current token is the first ':'.
+func (p *Parser) parseSliceExpression(
+	left ast.Expression,
+	tok *token.Token,
+	start ast.Expression,
+) ast.Expression {
+	expression := &ast.SliceExpression{Token: tok, Left: left, Start: start}
+
+	p.nextToken() // consume ':'
+	if !p.current.IsType(token.Colon) && !p.current.IsType(token.RBracket) {
+		expression.End = p.parseExpression(Lowest)
+		if expression.End == nil {
+			return nil
+		}
+		p.nextToken()
+	}
+	if p.current.IsType(token.Colon) {
+		p.nextToken()
+		if !p.current.IsType(token.RBracket) {
+			expression.Step = p.parseExpression(Lowest)
+			if expression.Step == nil {
+				return nil
+			}
+			p.nextToken()
+		}
+	}
+	if !p.current.IsType(token.RBracket) {
+		p.errors = append(
+			p.errors,
+			fmt.Sprintf("expected %s to close slice, got %s instead", token.RBracket, p.current.Type),
+		)
+		return nil
+	}
+	return expression
+}
+
+func (p *Parser) parseOptionalIndexExpression(left ast.Expression) ast.Expression {
+	expression := p.parseIndexExpression(left)
+	if expression == nil {
+		return nil
+	}
+	index, ok := expression.(*ast.IndexExpression)
+	if !ok {
+		p.errors = append(p.errors, fmt.Sprintf("cannot use %s with a slice", token.OptionalIndex))
+		return nil
+	}
+	index.Optional = true
+	return index
+}
+
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	expression := &ast.MemberExpression{
+		Token:    p.current,
+		Object:   left,
+		Optional: p.current.IsType(token.OptionalDot),
+	}
+	if !p.expectNext(token.Ident) {
+		return nil
+	}
+	expression.Property = &ast.Identifier{Token: p.current, Value: p.current.Literal}
+	return expression
+}
+
+func (p *Parser) parseCoalesceExpression(left ast.Expression) ast.Expression {
+	expression := &ast.CoalesceExpression{Token: p.current, Left: left}
+
+	p.nextToken()
+	expression.Right = p.parseExpression(Coalesce)
+	if expression.Right == nil {
+		return nil
+	}
+	return expression
+}
+
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{l: l}
 	p.nextToken()
@@ -445,6 +973,7 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.Ident, p.parseIdentifier)
 	p.registerPrefix(token.Int, p.parseInteger)
 	p.registerPrefix(token.String, p.parseString)
+	p.registerPrefix(token.InterpolatedString, p.parseInterpolatedString)
 	p.registerPrefix(token.Bang, p.parsePrefixExpression)
 	p.registerPrefix(token.Minus, p.parsePrefixExpression)
 	p.registerPrefix(token.True, p.parseBoolean)
@@ -454,17 +983,33 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.Function, p.parseFunctionLiteralExpression)
 	p.registerPrefix(token.LBracket, p.parseListExpression)
 	p.registerPrefix(token.LBrace, p.parseHashMapExpression)
+	p.registerPrefix(token.Match, p.parseMatchExpression)
+	p.registerPrefix(token.Spawn, p.parseSpawnExpression)
+	p.registerPrefix(token.Await, p.parseAwaitExpression)
+	p.registerPrefix(token.Select, p.parseSelectExpression)
+	p.registerPrefix(token.Null, p.parseNull)
+	p.registerPrefix(token.Macro, p.parseMacroLiteral)
 
 	p.infixFuncs = make(map[token.Type]infixFunc)
 	p.registerInfix(token.Plus, p.parseInfixExpression)
 	p.registerInfix(token.Minus, p.parseInfixExpression)
 	p.registerInfix(token.Slash, p.parseInfixExpression)
 	p.registerInfix(token.Asterisk, p.parseInfixExpression)
-	p.registerInfix(token.Eq, p.parseInfixExpression)
+	p.registerInfix(token.Eq, p.parseEqualsOrAssign)
 	p.registerInfix(token.NotEq, p.parseInfixExpression)
+	p.registerInfix(token.In, p.parseInfixExpression)
+	p.registerInfix(token.Not, p.parseNotInExpression)
+	p.registerInfix(token.Is, p.parseIsExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.Range, p.parseRangeExpression)
+	p.registerInfix(token.RangeEq, p.parseRangeExpression)
+	p.registerInfix(token.Question, p.parsePostfixExpression)
 	p.registerInfix(token.LParen, p.parseCallExpression)
 	p.registerInfix(token.LBracket, p.parseIndexExpression)
+	p.registerInfix(token.OptionalIndex, p.parseOptionalIndexExpression)
+	p.registerInfix(token.OptionalDot, p.parseMemberExpression)
+	p.registerInfix(token.Dot, p.parseMemberExpression)
+	p.registerInfix(token.Coalesce, p.parseCoalesceExpression)
 	return p
 }
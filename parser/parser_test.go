@@ -255,6 +255,9 @@ func TestParser_OperatorPrecedenceParsing(t *testing.T) {
 			"a * [1, 2, 3, 4][b * c] * d",
 			"((a * ([1, 2, 3, 4][(b * c)])) * d)",
 		},
+		{"f(x)? + 1", "((f(x)?) + 1)"},
+		{"-a?", "(-(a?))"},
+		{"a + xs[0]?", "(a + ((xs[0])?))"},
 		{
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
@@ -473,3 +476,614 @@ func checkErrors(t *testing.T, errors []string) {
 	}
 	require.Len(t, errors, 0)
 }
+
+func TestParser_MatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match (x) { 1 => "one", _ => "other" }`, `match (x) { 1 => "one", _ => "other" }`},
+		{`match (x) { -1 => a }`, `match (x) { (-1) => a }`},
+		{`match (x) { n if n > 1 => n * 2 }`, `match (x) { n if (n > 1) => (n * 2) }`},
+		{`match (x) { [a, b, ...rest] => rest }`, `match (x) { [a, b, ...rest] => rest }`},
+		{`match (x) { [a, ...] => a }`, `match (x) { [a, ..._] => a }`},
+		{`match (x) { {"a": [a], b} => a, }`, `match (x) { {"a": [a], "b": b} => a }`},
+		{`match (x) { _ => { return 1; } }`, `match (x) { _ => return 1; }`},
+		{"match (x) {\n  1 => { a }\n  _ => b\n}", `match (x) { 1 => a, _ => b }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Len(t, program.Statements, 1)
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+}
+
+func TestParser_MatchExpressionErrors(t *testing.T) {
+	tests := []string{
+		`match (x) { 1 "one" }`,
+		`match (x) { [...a, b] => a }`,
+		`match (x) { 1 => 1`,
+		`match x { _ => 1 }`,
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			p := New(lexer.New(input))
+			p.ParseProgram()
+			require.NotEmpty(t, p.Errors())
+		})
+	}
+}
+
+func TestParser_DestructuringLetStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let [a, b, ...rest] = xs;`, `let [a, b, ...rest] = xs;`},
+		{`let {name, age} = person;`, `let {"name": name, "age": age} = person;`},
+		{`let [_, {"a": a}] = xs`, `let [_, {"a": a}] = xs;`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Len(t, program.Statements, 1)
+			require.IsType(t, &ast.LetStatement{}, program.Statements[0])
+			require.Nil(t, program.Statements[0].(*ast.LetStatement).Name)
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+}
+
+func TestParser_FunctionLiteralPatterns(t *testing.T) {
+	p := New(lexer.New(`fn([a, b], c, {d}) { a }`))
+	program := p.ParseProgram()
+	checkErrors(t, p.Errors())
+	function := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteralExpression)
+	require.Len(t, function.Parameters, 3)
+	require.Len(t, function.Patterns, 3)
+	require.IsType(t, &ast.ListPattern{}, function.Patterns[0])
+	require.Nil(t, function.Patterns[1])
+	require.IsType(t, &ast.MapPattern{}, function.Patterns[2])
+	require.Equal(t, `fn([a, b], c, {"d": d}) { a }`, function.String())
+}
+
+func TestParser_ForStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`for (x in xs) { print(x); }`, `for (x in xs) { print(x) }`},
+		{`for ([k, v] in pairs(m)) { k }`, `for ([k, v] in pairs(m)) { k }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Len(t, program.Statements, 1)
+			require.IsType(t, &ast.ForStatement{}, program.Statements[0])
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+}
+
+func TestParser_AssignExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`x = 1`, `(x = 1)`},
+		{`x = y = 1 + 2`, `(x = (y = (1 + 2)))`},
+		{`x = a == b`, `(x = (a == b))`},
+		{`xs[0] = 1`, `((xs[0]) = 1)`},
+		{`const x = 1;`, `const x = 1;`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+
+	p := New(lexer.New(`1 = 2`))
+	p.ParseProgram()
+	require.NotEmpty(t, p.Errors())
+}
+
+func TestParser_TryStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`throw "boom";`, `throw "boom";`},
+		{`try { x } catch (e) { y }`, `try { x } catch (e) { y }`},
+		{`try { x } catch (KeyError e) { y } catch { z }`, `try { x } catch (KeyError e) { y } catch { z }`},
+		{`try { x } finally { z }`, `try { x } finally { z }`},
+		{`try { x } catch (e) { y } finally { z }`, `try { x } catch (e) { y } finally { z }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Len(t, program.Statements, 1)
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+
+	p := New(lexer.New(`try { x }`))
+	p.ParseProgram()
+	require.NotEmpty(t, p.Errors())
+}
+
+func TestParser_DeferStatement(t *testing.T) {
+	p := New(lexer.New(`fn() { defer close(f); }`))
+	program := p.ParseProgram()
+	checkErrors(t, p.Errors())
+	function := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteralExpression)
+	require.IsType(t, &ast.DeferStatement{}, function.Body.Statements[0])
+	require.Equal(t, "defer close(f);", function.Body.String())
+}
+
+func TestParser_SliceExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`xs[1:2]`, `(xs[1:2])`},
+		{`xs[1:]`, `(xs[1:])`},
+		{`xs[:2]`, `(xs[:2])`},
+		{`xs[:]`, `(xs[:])`},
+		{`xs[::2]`, `(xs[::2])`},
+		{`xs[a + 1:-1:-1]`, `(xs[(a + 1):(-1):(-1)])`},
+		{`xs[1:2][0]`, `((xs[1:2])[0])`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+
+	p := New(lexer.New(`xs[1:2:3:4]`))
+	p.ParseProgram()
+	require.NotEmpty(t, p.Errors())
+}
+
+func TestParser_RangeExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`0..10`, `(0..10)`},
+		{`0..=10`, `(0..=10)`},
+		{`a..b + 1`, `(a..(b + 1))`},
+		{`0..10 step 2`, `(0..10 step 2)`},
+		{`0..n step -1`, `(0..n step (-1))`},
+		{`a < 0..1`, `(a < (0..1))`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+}
+
+func TestParser_Comprehensions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[x * x for x in xs]`, `[(x * x) for x in xs]`},
+		{`[x for x in xs if x > 0]`, `[x for x in xs if (x > 0)]`},
+		{`[x for x in xs if a if b]`, `[x for x in xs if a if b]`},
+		{`[[x, y] for x in xs for y in ys]`, `[[x, y] for x in xs for y in ys]`},
+		{`{k: v for k, v in items(m)}`, `{k: v for [k, v] in items(m)}`},
+		{`{k: 1 for [k, _] in m if k}`, `{k: 1 for [k, _] in m if k}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Len(t, program.Statements, 1)
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+}
+
+func TestParser_NullSafety(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`null`, `null`},
+		{`a ?? b`, `(a ?? b)`},
+		{`a ?? b == c`, `(a ?? (b == c))`},
+		{`x = a ?? b`, `(x = (a ?? b))`},
+		{`a?.b?.c`, `((a?.b)?.c)`},
+		{`a?[0]`, `(a?[0])`},
+		{`a?.b?[1] ?? 2`, `(((a?.b)?[1]) ?? 2)`},
+		{`f()?.b`, `(f()?.b)`},
+		{`a? + 1`, `((a?) + 1)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+}
+
+func TestParser_MembershipOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`a in b`, `(a in b)`},
+		{`a not in b`, `(a not in b)`},
+		{`a is b`, `(a is b)`},
+		{`a is not null`, `(a is not null)`},
+		{`a + 1 in 0..n`, `((a + 1) in (0..n))`},
+		{`x = a in b`, `(x = (a in b))`},
+		{`a in b == c`, `((a in b) == c)`},
+		{`let not = 5;`, `let not = 5;`},
+		{`let is = fn(x) { x }; is(not)`, `let is = fn(x) { x };is(not)`},
+		{`not is not is`, `(not is not is)`},
+		{`[is, not] not in xs`, `([is, not] not in xs)`},
+		{`not = is`, `(not = is)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+
+	p := New(lexer.New(`a not b`))
+	p.ParseProgram()
+	require.NotEmpty(t, p.Errors())
+}
+
+func TestParser_StructStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`struct Point { x, y }`, `struct Point { x, y }`},
+		{`struct Empty {}`, `struct Empty {  }`},
+		{`struct P { x }; P(1)`, `struct P { x }P(1)`},
+		{
+			"struct Point {\n x\n y\n fn norm() { self.x * self.x + self.y * self.y }\n}",
+			`struct Point { x, y; fn norm() { (((self.x) * (self.x)) + ((self.y) * (self.y))) } }`,
+		},
+		{`p.x = 1`, `((p.x) = 1)`},
+		{`a.b.c(1)`, `((a.b).c)(1)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+
+	for _, input := range []string{`struct { x }`, `struct P { 1 }`, `struct P { x`, `p?.x = 1`} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		require.NotEmpty(t, p.Errors(), input)
+	}
+}
+
+func TestParser_EnumStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`enum Color { Red, Green, Blue }`, `enum Color { Red, Green, Blue }`},
+		{`enum E { A }; E.A`, `enum E { A }(E.A)`},
+		{"enum Shape {\n Circle(r)\n Rect(w, h)\n Empty\n}", `enum Shape { Circle(r), Rect(w, h), Empty }`},
+		{`enum Unit { Nothing() }`, `enum Unit { Nothing() }`},
+		{
+			`match (s) { Shape.Circle(r) => r, Shape.Rect(w, _) => w, Shape.Empty => 0 }`,
+			`match (s) { Shape.Circle(r) => r, Shape.Rect(w, _) => w, Shape.Empty => 0 }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+
+	for _, input := range []string{`enum { A }`, `enum E { A(1) }`, `enum E { A`, `match (x) { E.1 => 1 }`} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		require.NotEmpty(t, p.Errors(), input)
+	}
+}
+
+func TestParser_ImportExport(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "lib/strings" as s`, `import "lib/strings" as s;`},
+		{`import "lib/strings";`, `import "lib/strings" as strings;`},
+		{`import "util.mitch"`, `import "util.mitch" as util;`},
+		{`export let x = 1;`, `export let x = 1;`},
+		{`export struct P { x }`, `export struct P { x }`},
+		{`let as = 1;`, `let as = 1;`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+
+	for _, input := range []string{`import s`, `import "s" as`, `export 1`, `export fn() {}`} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		require.NotEmpty(t, p.Errors(), input)
+	}
+}
+
+func TestParser_YieldStatement(t *testing.T) {
+	p := New(lexer.New(`fn() { yield 1; fn() { 2 } }; fn() { fn() { yield 1 } }`))
+	program := p.ParseProgram()
+	checkErrors(t, p.Errors())
+	require.Equal(t, `fn() { yield 1;fn() { 2 } }fn() { fn() { yield 1; } }`, program.String())
+
+	outer := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteralExpression)
+	inner := outer.Body.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteralExpression)
+	require.True(t, outer.Generator)
+	require.False(t, inner.Generator)
+
+	outer = program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteralExpression)
+	inner = outer.Body.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteralExpression)
+	require.False(t, outer.Generator)
+	require.True(t, inner.Generator)
+
+	p = New(lexer.New(`yield 1`))
+	p.ParseProgram()
+	require.Equal(t, []string{"yield outside of a function"}, p.Errors())
+}
+
+func TestParser_Concurrency(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`spawn f(1, 2)`, `(spawn f(1, 2))`},
+		{`await spawn f()`, `(await (spawn f()))`},
+		{`await t + 1`, `((await t) + 1)`},
+		{
+			`select { v = recv(a) => v, send(b, 1) => { 2 }, recv(c) => 3, default => 4 }`,
+			`select { v = recv(a) => v, send(b, 1) => 2, recv(c) => 3, default => 4 }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+
+	for _, input := range []string{
+		`spawn f`,
+		`select { v = send(a, 1) => 1 }`,
+		`select { recv(a, 1) => 1 }`,
+		`select { wait(a) => 1 }`,
+		`select { default => 1, default => 2 }`,
+		`select { recv(a => 1 }`,
+	} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		require.NotEmpty(t, p.Errors(), input)
+	}
+}
+
+func TestParser_KeywordArgumentsAndGroups(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`f(1, workers = 2)`, `f(1, workers = 2)`},
+		{`f(a = 1 + 2, b = g(c = 3))`, `f(a = (1 + 2), b = g(c = 3))`},
+		{`f(a == 1)`, `f((a == 1))`},
+		{`group { spawn f(); spawn g(); }`, `group { (spawn f())(spawn g()) }`},
+		{`group { f(); }; g()`, `group { f() }g()`},
+		{`let group = 1; group + 1`, `let group = 1;(group + 1)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+
+	for _, input := range []string{
+		`f(a = 1, 2)`,
+		`f(1`,
+		`f(1,`,
+		`f(a = 1`,
+	} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		require.NotEmpty(t, p.Errors(), input)
+	}
+}
+
+func TestParser_MacroLiteral(t *testing.T) {
+	p := New(lexer.New(`macro(x, y) { x + y; }`))
+	program := p.ParseProgram()
+	checkErrors(t, p.Errors())
+	require.Len(t, program.Statements, 1)
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	macro, ok := stmt.Expression.(*ast.MacroLiteral)
+	require.True(t, ok)
+	require.Len(t, macro.Parameters, 2)
+	require.Equal(t, "x", macro.Parameters[0].Value)
+	require.Equal(t, "y", macro.Parameters[1].Value)
+	require.Equal(t, "(x + y)", macro.Body.String())
+	require.Equal(t, "macro(x, y) { (x + y) }", macro.String())
+
+	for _, input := range []string{
+		`macro([a]) { a }`,
+		`macro(a) { yield a; }`,
+		`macro(a`,
+	} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		require.NotEmpty(t, p.Errors(), input)
+	}
+}
+
+func TestParser_InterpolatedString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"hello ${name}, you have ${count + 1} items"`, `"hello ${name}, you have ${(count + 1)} items"`},
+		{`"${a}${b}"`, `"${a}${b}"`},
+		{`"${"inner ${x}"}"`, `"${"inner ${x}"}"`},
+		{`"${f(1)[0]}" + "!"`, `("${(f(1)[0])}" + "!")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			program := p.ParseProgram()
+			checkErrors(t, p.Errors())
+			require.Equal(t, tt.expected, program.String())
+		})
+	}
+
+	p := New(lexer.New(`"a ${x} b"`))
+	program := p.ParseProgram()
+	checkErrors(t, p.Errors())
+	str := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.InterpolatedString)
+	require.Len(t, str.Parts, 3)
+	require.IsType(t, &ast.StringLiteral{}, str.Parts[0])
+	require.IsType(t, &ast.Identifier{}, str.Parts[1])
+
+	for _, input := range []string{
+		`"${}"`,
+		`"${let x = 1;}"`,
+		`"${1 +}"`,
+		`"${a; b}"`,
+		`"${x"`,
+		`"${s(1}"`,
+	} {
+		p := New(lexer.New(input))
+		p.ParseProgram()
+		require.NotEmpty(t, p.Errors(), input)
+	}
+}
+
+func TestParser_DocComments(t *testing.T) {
+	input := `/// The answer.
+let answer = 42;
+/// Adds a and b.
+let add = fn(a, b) { a + b };
+let g = /// Doubles x.
+fn(x) { x * 2 };
+/// Exported.
+export let sub = fn(a, b) { a - b };
+struct P {
+    x
+    /// Returns x.
+    fn get() { return self.x; }
+}
+let undocumented = fn() { 1 };
+/* not /* a doc */ comment */ let c = 1;`
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkErrors(t, p.Errors())
+	require.Len(t, program.Statements, 7)
+
+	answer := program.Statements[0].(*ast.LetStatement)
+	require.Equal(t, "The answer.", answer.Doc)
+
+	add := program.Statements[1].(*ast.LetStatement)
+	require.Equal(t, "Adds a and b.", add.Doc)
+	require.Equal(t, "Adds a and b.", add.Value.(*ast.FunctionLiteralExpression).Doc)
+
+	g := program.Statements[2].(*ast.LetStatement)
+	require.Empty(t, g.Doc)
+	require.Equal(t, "Doubles x.", g.Value.(*ast.FunctionLiteralExpression).Doc)
+
+	sub := program.Statements[3].(*ast.ExportStatement).Statement.(*ast.LetStatement)
+	require.Equal(t, "Exported.", sub.Doc)
+	require.Equal(t, "Exported.", sub.Value.(*ast.FunctionLiteralExpression).Doc)
+
+	method := program.Statements[4].(*ast.StructStatement).Methods[0]
+	require.Equal(t, "Returns x.", method.Function.Doc)
+
+	undocumented := program.Statements[5].(*ast.LetStatement)
+	require.Empty(t, undocumented.Doc)
+	require.Empty(t, program.Statements[6].(*ast.LetStatement).Doc)
+}
+
+func TestParser_IllegalTokens(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`1 /* never closed`, []string{"unterminated block comment"}},
+		{`let x /* never closed`, []string{"unterminated block comment"}},
+		{`"${x"`, []string{"unterminated string interpolation"}},
+		{`a \ b`, []string{`illegal character '\\'`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+			p.ParseProgram()
+			require.Equal(t, tt.expected, p.Errors())
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package parser
+
+import (
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/token"
+)
+
+// parseMatchExpression parses `match (subject) { pattern => body, ... }`.
+// As in list and map literals the commas between arms are optional, so
+// arms can also be written one per line.
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: p.current}
+	expression.Arms = []*ast.MatchArm{}
+
+	if !p.expectNext(token.LParen) {
+		return nil
+	}
+	p.nextToken()
+	expression.Subject = p.parseExpression(Lowest)
+	if expression.Subject == nil {
+		return nil
+	}
+	if !p.expectNext(token.RParen) {
+		return nil
+	}
+	if !p.expectNext(token.LBrace) {
+		return nil
+	}
+	p.nextToken()
+	for !p.current.IsType(token.RBrace) {
+		if p.current.IsType(token.EOF) {
+			p.errors = append(p.errors, "unterminated match expression")
+			return nil
+		}
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		expression.Arms = append(expression.Arms, arm)
+		if p.next.IsType(token.Comma) {
+			p.nextToken()
+		}
+		p.nextToken()
+	}
+	return expression
+}
+
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{Token: p.current}
+
+	arm.Pattern = p.parsePattern()
+	if arm.Pattern == nil {
+		return nil
+	}
+	if p.next.IsType(token.If) {
+		p.nextToken()
+		p.nextToken()
+		arm.Guard = p.parseExpression(Lowest)
+		if arm.Guard == nil {
+			return nil
+		}
+	}
+	if !p.expectNext(token.Arrow) {
+		return nil
+	}
+	p.nextToken()
+	arm.Body = p.parseArmBody()
+	if arm.Body == nil {
+		return nil
+	}
+	return arm
+}
+
+// parseArmBody parses the block or expression after the => of an arm
+func (p *Parser) parseArmBody() ast.Statement {
+	if p.current.IsType(token.LBrace) {
+		return p.parseBlockStatement()
+	}
+	body := &ast.ExpressionStatement{Token: p.current}
+	body.Expression = p.parseExpression(Lowest)
+	if body.Expression == nil {
+		return nil
+	}
+	return body
+}
+
+// parsePattern parses the pattern starting at the current token. The
+// current token is left on the last token of the pattern.
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.current.Type {
+	case token.Ident:
+		if p.current.Literal == "_" {
+			return &ast.WildcardPattern{Token: p.current}
+		}
+		ident := &ast.Identifier{Token: p.current, Value: p.current.Literal}
+		if p.next.IsType(token.Dot) {
+			return p.parseVariantPattern(ident)
+		}
+		return &ast.BindingPattern{Token: p.current, Name: ident}
+	case token.Int, token.String, token.True, token.False, token.Minus, token.Null:
+		pattern := &ast.LiteralPattern{Token: p.current}
+		pattern.Value = p.parseExpression(Prefix)
+		if pattern.Value == nil {
+			return nil
+		}
+		return pattern
+	case token.LBracket:
+		return p.parseListPattern()
+	case token.LBrace:
+		return p.parseMapPattern()
+	default:
+		p.errors = append(
+			p.errors,
+			fmt.Sprintf("unexpected token %s in pattern", p.current.Type),
+		)
+		return nil
+	}
+}
+
+func (p *Parser) parseListPattern() ast.Pattern {
+	pattern := &ast.ListPattern{Token: p.current}
+	pattern.Elements = []ast.Pattern{}
+
+	p.nextToken()
+	for !p.current.IsType(token.RBracket) {
+		if p.current.IsType(token.EOF) {
+			p.errors = append(p.errors, "unterminated list pattern")
+			return nil
+		}
+		if pattern.Rest != nil {
+			p.errors = append(p.errors, "rest pattern must be the last element of a list pattern")
+			return nil
+		}
+		if p.current.IsType(token.Ellipsis) {
+			if p.next.IsType(token.Ident) {
+				p.nextToken()
+				pattern.Rest = p.parsePattern()
+			} else {
+				pattern.Rest = &ast.WildcardPattern{Token: p.current}
+			}
+		} else {
+			element := p.parsePattern()
+			if element == nil {
+				return nil
+			}
+			pattern.Elements = append(pattern.Elements, element)
+		}
+		if p.next.IsType(token.Comma) {
+			p.nextToken()
+		}
+		p.nextToken()
+	}
+	return pattern
+}
+
+func (p *Parser) parseMapPattern() ast.Pattern {
+	pattern := &ast.MapPattern{Token: p.current}
+	pattern.Keys = []ast.Expression{}
+	pattern.Values = []ast.Pattern{}
+
+	p.nextToken()
+	for !p.current.IsType(token.RBrace) {
+		if p.current.IsType(token.EOF) {
+			p.errors = append(p.errors, "unterminated map pattern")
+			return nil
+		}
+		if p.current.IsType(token.Ident) && (p.next.IsType(token.Comma) || p.next.IsType(token.RBrace)) {
+			// {name} is shorthand for {"name": name}
+			key := &ast.StringLiteral{Token: p.current, Value: p.current.Literal}
+			pattern.Keys = append(pattern.Keys, key)
+			pattern.Values = append(pattern.Values, p.parsePattern())
+		} else {
+			key := p.parseExpression(Lowest)
+			if key == nil {
+				return nil
+			}
+			if !p.expectNext(token.Colon) {
+				return nil
+			}
+			p.nextToken()
+			value := p.parsePattern()
+			if value == nil {
+				return nil
+			}
+			pattern.Keys = append(pattern.Keys, key)
+			pattern.Values = append(pattern.Values, value)
+		}
+		if p.next.IsType(token.Comma) {
+			p.nextToken()
+		}
+		p.nextToken()
+	}
+	return pattern
+}
+
+// parseVariantPattern parses `Enum.Variant` or `Enum.Variant(patterns)`.
+// The current token is the name of the enum.
+func (p *Parser) parseVariantPattern(enum *ast.Identifier) ast.Pattern {
+	pattern := &ast.VariantPattern{Token: p.current, Enum: enum}
+	p.nextToken()
+	if !p.expectNext(token.Ident) {
+		return nil
+	}
+	pattern.Variant = &ast.Identifier{Token: p.current, Value: p.current.Literal}
+	if !p.next.IsType(token.LParen) {
+		return pattern
+	}
+	p.nextToken()
+	pattern.Fields = []ast.Pattern{}
+
+	p.nextToken()
+	for !p.current.IsType(token.RParen) {
+		if p.current.IsType(token.EOF) {
+			p.errors = append(p.errors, "unterminated variant pattern")
+			return nil
+		}
+		field := p.parsePattern()
+		if field == nil {
+			return nil
+		}
+		pattern.Fields = append(pattern.Fields, field)
+		if p.next.IsType(token.Comma) {
+			p.nextToken()
+		}
+		p.nextToken()
+	}
+	return pattern
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"fmt"
+	"mitchlang/ast"
+	"mitchlang/token"
+)
+
+// parseStructStatement parses
+//
+//	struct Point {
+//	    x, y
+//	    fn norm() { return self.x * self.x + self.y * self.y; }
+//	}
+func (p *Parser) parseStructStatement() *ast.StructStatement {
+	statement := &ast.StructStatement{Token: p.current}
+	statement.Fields = []*ast.Identifier{}
+	statement.Methods = []*ast.MethodDeclaration{}
+
+	if !p.expectNext(token.Ident) {
+		return nil
+	}
+	statement.Name = &ast.Identifier{Token: p.current, Value: p.current.Literal}
+	if !p.expectNext(token.LBrace) {
+		return nil
+	}
+	p.nextToken()
+	for !p.current.IsType(token.RBrace) {
+		switch p.current.Type {
+		case token.Ident:
+			field := &ast.Identifier{Token: p.current, Value: p.current.Literal}
+			statement.Fields = append(statement.Fields, field)
+		case token.Function:
+			method := p.parseMethodDeclaration()
+			if method == nil {
+				return nil
+			}
+			statement.Methods = append(statement.Methods, method)
+		case token.Comma, token.SemiColon:
+		case token.EOF:
+			p.errors = append(p.errors, "unterminated struct declaration")
+			return nil
+		default:
+			p.errors = append(
+				p.errors,
+				fmt.Sprintf("unexpected token %s in struct declaration", p.current.Type),
+			)
+			return nil
+		}
+		p.nextToken()
+	}
+	if p.next.IsType(token.SemiColon) {
+		p.nextToken()
+	}
+	return statement
+}
+
+// parseMethodDeclaration parses `fn name(params) { body }`
+func (p *Parser) parseMethodDeclaration() *ast.MethodDeclaration {
+	method := &ast.MethodDeclaration{Token: p.current}
+	method.Function = &ast.FunctionLiteralExpression{Token: p.current, Doc: p.current.Doc}
+
+	if !p.expectNext(token.Ident) {
+		return nil
+	}
+	method.Name = &ast.Identifier{Token: p.current, Value: p.current.Literal}
+	if !p.parseFunction(method.Function) {
+		return nil
+	}
+	return method
+}
@@ -37,7 +37,13 @@ func Start(in io.Reader, out io.Writer) {
 			printParserErrors(out, p.Errors())
 			continue
 		}
-		obj := eval.Eval(program, env)
+		eval.DefineMacros(program, env)
+		expanded, err := eval.ExpandMacros(program, env)
+		if err != nil {
+			_, _ = io.WriteString(out, err.Inspect()+"\n")
+			continue
+		}
+		obj := eval.Eval(expanded, env)
 		if obj != nil && obj.Type() != object.TypeNull {
 			_, _ = io.WriteString(out, obj.Inspect())
 			_, _ = io.WriteString(out, "\n")
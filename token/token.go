@@ -20,6 +20,9 @@ const (
 	LT       Type = "<"
 	GT       Type = ">"
 
+	// InterpolatedString is a string with embedded ${expressions}
+	InterpolatedString Type = "INTERPOLATED_STRING"
+
 	Eq    Type = "="
 	NotEq Type = "!="
 
@@ -27,6 +30,16 @@ const (
 	SemiColon Type = ";"
 	Colon     Type = ":"
 	Dot       Type = "."
+	Arrow     Type = "=>"
+	Ellipsis  Type = "..."
+	Range     Type = ".."
+	RangeEq   Type = "..="
+	Question  Type = "?"
+	Coalesce  Type = "??"
+	// OptionalDot and OptionalIndex are the safe navigation forms of
+	// Dot and LBracket
+	OptionalDot   Type = "?."
+	OptionalIndex Type = "?["
 
 	LParen   Type = "("
 	RParen   Type = ")"
@@ -42,11 +55,37 @@ const (
 	If       Type = "if"
 	Else     Type = "else"
 	Return   Type = "return"
+	Match    Type = "match"
+	For      Type = "for"
+	In       Type = "in"
+	Const    Type = "const"
+	Try      Type = "try"
+	Catch    Type = "catch"
+	Finally  Type = "finally"
+	Throw    Type = "throw"
+	Defer    Type = "defer"
+	Null     Type = "null"
+	Not      Type = "not"
+	Is       Type = "is"
+	Struct   Type = "struct"
+	Enum     Type = "enum"
+	Import   Type = "import"
+	Export   Type = "export"
+	Yield    Type = "yield"
+	Spawn    Type = "spawn"
+	Await    Type = "await"
+	Select   Type = "select"
+	Macro    Type = "macro"
 )
 
 type Token struct {
 	Type    Type
 	Literal string
+	// Parts holds the pieces of an InterpolatedString, alternating between
+	// text and the source of an embedded expression, starting with text
+	Parts []string
+	// Doc holds the /// doc comments written before the token
+	Doc string
 }
 
 func (t *Token) IsType(tt Type) bool { return t.Type == tt }
@@ -69,13 +108,49 @@ func New(t Type, literal ...byte) *Token {
 }
 
 var keywords = map[string]Type{
-	"fn":     Function,
-	"let":    Let,
-	"true":   True,
-	"false":  False,
-	"if":     If,
-	"else":   Else,
-	"return": Return,
+	"fn":      Function,
+	"let":     Let,
+	"true":    True,
+	"false":   False,
+	"if":      If,
+	"else":    Else,
+	"return":  Return,
+	"match":   Match,
+	"for":     For,
+	"in":      In,
+	"const":   Const,
+	"try":     Try,
+	"catch":   Catch,
+	"finally": Finally,
+	"throw":   Throw,
+	"defer":   Defer,
+	"null":    Null,
+	"struct":  Struct,
+	"enum":    Enum,
+	"import":  Import,
+	"export":  Export,
+	"yield":   Yield,
+	"spawn":   Spawn,
+	"await":   Await,
+	"select":  Select,
+	"macro":   Macro,
+}
+
+// contextualKeywords are only keywords when used as infix operators, so
+// they can still be used as names
+var contextualKeywords = map[string]Type{
+	"not": Not,
+	"is":  Is,
+}
+
+// InfixType returns the type of t when it is used as an infix operator
+func (t *Token) InfixType() Type {
+	if t.Type == Ident {
+		if tokenType, ok := contextualKeywords[t.Literal]; ok {
+			return tokenType
+		}
+	}
+	return t.Type
 }
 
 func lookupIdent(ident string) Type {